@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const cacheUsage = `Usage: serve cache [-config file] <command>
+
+Inspects the HTTP response cache of the providers.
+
+Commands:
+  list   list cached responses
+  clear  remove all cached responses
+`
+
+// runCache implements the "cache" subcommand.
+func runCache(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("cache", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to YAML config file (env "+envPrefix+"CONFIG)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), cacheUsage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one command")
+	}
+
+	var configArgs []string
+	if *configPath != "" {
+		configArgs = []string{"-config", *configPath}
+	}
+	cfg, _, err := loadConfig(configArgs, os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	store := cfg.HTTP.cache()
+	if store == nil {
+		return fmt.Errorf("cache is disabled (http.cache_dir is empty)")
+	}
+
+	switch fs.Arg(0) {
+	case "list":
+		entries, err := store.Entries()
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "URL\tSIZE\tSTORED\tEXPIRES")
+		now := time.Now()
+		for _, e := range entries {
+			expires := e.ExpiresAt.Format(time.DateTime)
+			if !e.Fresh(now) {
+				expires = "stale"
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", e.URL, len(e.Body), e.StoredAt.Format(time.DateTime), expires)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d entries in %s\n", len(entries), store.Dir())
+	case "clear":
+		n, err := store.Clear()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Removed %d entries from %s\n", n, store.Dir())
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+
+	return nil
+}
@@ -1,43 +1,482 @@
 package main
 
 import (
+	"errors"
 	"flag"
-	"log"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/app"
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpcache"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpclient"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/provider"
+	"gopkg.in/yaml.v3"
 )
 
+// envPrefix prefixes all environment variables that override config values.
+const envPrefix = "KINO_"
+
+// Config is the configuration of the server. Values are resolved in this
+// order, later ones taking precedence: defaults, config file, environment
+// variables, command line flags.
 type Config struct {
-	Addr         string
-	SyncInterval time.Duration
-	TemplateDir  string
-	StaticDir    string
+	Server    ServerConfig     `yaml:"server"`
+	Storage   StorageConfig    `yaml:"storage"`
+	Sync      SyncConfig       `yaml:"sync"`
+	HTTP      HTTPConfig       `yaml:"http"`
+	Providers []ProviderConfig `yaml:"providers"`
+	// Prices are default ticket prices keyed by cinema name, used if the
+	// provider does not know them.
+	Prices map[string]PriceConfig `yaml:"prices,omitempty"`
+	// TitleAliases lists groups of titles naming the same film, like the
+	// original and the German release title, for searching.
+	TitleAliases [][]string `yaml:"title_aliases,omitempty"`
+	// CinemaGroups maps names like "Kreuzberg/Neukölln" to cinema names
+	// that can be selected at once.
+	CinemaGroups map[string][]string `yaml:"cinema_groups,omitempty"`
+	// DayRollover is the time like "04:00" at which a new cinema day
+	// begins, late shows before belong to the previous day. Empty means
+	// midnight.
+	DayRollover string `yaml:"day_rollover,omitempty"`
+}
+
+type ServerConfig struct {
+	Host string `yaml:"host"`
+	Port string `yaml:"port"`
+	// Templates and Static are directories replacing the embedded templates
+	// and static files, e.g. to see changes without rebuilding. Templates
+	// are parsed again for every request then.
+	Templates string `yaml:"templates,omitempty"`
+	Static    string `yaml:"static,omitempty"`
+}
+
+func (s ServerConfig) Addr() string {
+	return s.Host + ":" + s.Port
+}
+
+type StorageConfig struct {
+	// Driver selects the storage backend. Only "memory" is supported yet.
+	Driver string `yaml:"driver"`
+}
+
+type SyncConfig struct {
+	// Interval between background syncs. Zero disables background syncing.
+	Interval Duration `yaml:"interval"`
+	// Jitter delays every sync by a random duration up to Jitter.
+	Jitter Duration `yaml:"jitter,omitempty"`
+	// QuietHours are windows like "01:00-07:00" in Europe/Berlin time
+	// during which no provider is scraped.
+	QuietHours []string `yaml:"quiet_hours,omitempty"`
+	// Retry configures retries of transient scrape failures.
+	Retry RetryConfig `yaml:"retry"`
+	// Breaker configures the per-provider circuit breaker.
+	Breaker BreakerConfig `yaml:"breaker"`
 }
 
-func parseFlags() Config {
-	host := flag.String("host", "localhost", "Host")
-	port := flag.String("port", "8080", "Port to listen on")
-	syncInterval := flag.Duration("sync-interval", 30*time.Minute, "Background sync interval (0 to disable)")
-	templateDir := flag.String("templates", "web/templates", "Template directory")
-	staticDir := flag.String("static", "web/static", "Static files directory")
-	flag.Parse()
+type RetryConfig struct {
+	Attempts       int      `yaml:"attempts"`
+	InitialBackoff Duration `yaml:"initial_backoff"`
+	MaxBackoff     Duration `yaml:"max_backoff"`
+}
 
-	templateDirAbs, err := filepath.Abs(*templateDir)
+type BreakerConfig struct {
+	// Threshold is the number of consecutive failed syncs that open the
+	// breaker.
+	Threshold int      `yaml:"threshold"`
+	Cooldown  Duration `yaml:"cooldown"`
+}
+
+// schedule returns the default schedule of all providers. The config must be
+// valid.
+func (s SyncConfig) schedule() app.Schedule {
+	quietHours, _ := parseQuietHours(s.QuietHours)
+	return app.Schedule{
+		Interval:   time.Duration(s.Interval),
+		Jitter:     time.Duration(s.Jitter),
+		QuietHours: quietHours,
+	}
+}
+
+// HTTPConfig holds the defaults of the HTTP client used by providers.
+type HTTPConfig struct {
+	UserAgent string   `yaml:"user_agent"`
+	Timeout   Duration `yaml:"timeout"`
+	// MinInterval is the minimum delay between requests to the same host.
+	MinInterval  Duration `yaml:"min_interval"`
+	IgnoreRobots bool     `yaml:"ignore_robots"`
+	// CacheDir stores responses on disk. Empty disables the cache.
+	CacheDir string `yaml:"cache_dir"`
+	// CacheMinTTL keeps responses cached for at least this long, even if
+	// the website forbids caching. Zero, the default, honours the caching
+	// headers of the website.
+	CacheMinTTL Duration `yaml:"cache_min_ttl"`
+}
+
+// cache returns the response cache or nil if it is disabled.
+func (h HTTPConfig) cache() *httpcache.Store {
+	if h.CacheDir == "" {
+		return nil
+	}
+	return httpcache.New(h.CacheDir, time.Duration(h.CacheMinTTL))
+}
+
+// dayRollover returns DayRollover as offset since midnight.
+func (c Config) dayRollover() (time.Duration, error) {
+	if c.DayRollover == "" {
+		return 0, nil
+	}
+	rollover, err := domain.ParseClock(c.DayRollover)
 	if err != nil {
-		log.Printf("Warning: could not resolve template dir, using relative: %v", err)
-		templateDirAbs = *templateDir
+		return 0, err
+	}
+	if rollover >= 12*time.Hour {
+		return 0, fmt.Errorf("must be before 12:00, got %q", c.DayRollover)
 	}
+	return rollover, nil
+}
+
+// Duration is a time.Duration that is written as a string like "30m" in
+// config files.
+type Duration time.Duration
+
+func (d Duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
+}
 
-	staticDirAbs, err := filepath.Abs(*staticDir)
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := time.ParseDuration(node.Value)
 	if err != nil {
-		log.Printf("Warning: could not resolve static dir, using relative: %v", err)
-		staticDirAbs = *staticDir
+		return fmt.Errorf("line %d: invalid duration %q, use a value like \"30m\"", node.Line, node.Value)
 	}
+	*d = Duration(parsed)
 
+	return nil
+}
+
+func defaultConfig() Config {
 	return Config{
-		Addr:         *host + ":" + *port,
-		SyncInterval: *syncInterval,
-		TemplateDir:  templateDirAbs,
-		StaticDir:    staticDirAbs,
+		Server: ServerConfig{
+			Host: "localhost",
+			Port: "8080",
+		},
+		Storage: StorageConfig{
+			Driver: "memory",
+		},
+		Sync: SyncConfig{
+			Interval: Duration(30 * time.Minute),
+			Retry: RetryConfig{
+				Attempts:       3,
+				InitialBackoff: Duration(5 * time.Second),
+				MaxBackoff:     Duration(2 * time.Minute),
+			},
+			Breaker: BreakerConfig{
+				Threshold: 3,
+				Cooldown:  Duration(time.Hour),
+			},
+		},
+		HTTP: HTTPConfig{
+			UserAgent:   httpclient.DefaultUserAgent,
+			Timeout:     Duration(30 * time.Second),
+			MinInterval: Duration(time.Second),
+			CacheDir:    httpcache.DefaultDir(),
+		},
+		Providers: []ProviderConfig{
+			{ID: "babylon"},
+			{ID: "yorck"},
+		},
+	}
+}
+
+// loadConfig resolves the configuration from args, the environment and the
+// config file. printConfig reports whether -print-config was given.
+func loadConfig(args []string, getenv func(string) string) (cfg Config, printConfig bool, err error) {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to YAML config file (env "+envPrefix+"CONFIG)")
+	host := fs.String("host", "", "Host")
+	port := fs.String("port", "", "Port to listen on")
+	syncInterval := fs.Duration("sync-interval", 0, "Background sync interval (0 to disable)")
+	templateDir := fs.String("templates", "", "Template directory replacing the embedded templates")
+	staticDir := fs.String("static", "", "Static files directory replacing the embedded files")
+	providerIDs := fs.String("providers", "", "Comma-separated IDs of enabled providers, replaces the providers section")
+	icalFeeds := fs.String("ical", "", "Comma-separated iCal feed URLs or files")
+	fs.BoolVar(&printConfig, "print-config", false, "Print the effective configuration and exit")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, false, err
+	}
+
+	cfg = defaultConfig()
+
+	path := *configPath
+	if path == "" {
+		path = getenv(envPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return Config{}, false, err
+		}
+	}
+
+	if err := cfg.applyEnv(getenv); err != nil {
+		return Config{}, false, err
+	}
+
+	// flags only take precedence if given explicitly
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+		switch f.Name {
+		case "host":
+			cfg.Server.Host = *host
+		case "port":
+			cfg.Server.Port = *port
+		case "sync-interval":
+			cfg.Sync.Interval = Duration(*syncInterval)
+		case "templates":
+			cfg.Server.Templates = *templateDir
+		case "static":
+			cfg.Server.Static = *staticDir
+		}
+	})
+	// -providers replaces the providers, so the feeds of -ical are added
+	// after it, whatever the order of the flags
+	if given["providers"] {
+		cfg.Providers = nil
+		for _, id := range splitList(*providerIDs) {
+			cfg.Providers = append(cfg.Providers, ProviderConfig{ID: id})
+		}
+	}
+	if feeds := splitList(*icalFeeds); given["ical"] && len(feeds) > 0 {
+		cfg.Providers = append(cfg.Providers, ProviderConfig{ID: "ical", Sources: feeds})
+	}
+
+	if err := cfg.validate(); err != nil {
+		return Config{}, false, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	if cfg.Server.Templates != "" {
+		cfg.Server.Templates = absPath(cfg.Server.Templates)
+	}
+	if cfg.Server.Static != "" {
+		cfg.Server.Static = absPath(cfg.Server.Static)
+	}
+
+	return cfg, printConfig, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parsing config file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *Config) applyEnv(getenv func(string) string) error {
+	fields := map[string]*string{
+		"SERVER_HOST":      &c.Server.Host,
+		"SERVER_PORT":      &c.Server.Port,
+		"SERVER_TEMPLATES": &c.Server.Templates,
+		"SERVER_STATIC":    &c.Server.Static,
+		"STORAGE_DRIVER":   &c.Storage.Driver,
+		"HTTP_CACHE_DIR":   &c.HTTP.CacheDir,
+		"DAY_ROLLOVER":     &c.DayRollover,
+	}
+	for name, field := range fields {
+		if v := getenv(envPrefix + name); v != "" {
+			*field = v
+		}
+	}
+
+	if v := getenv(envPrefix + "SYNC_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%sSYNC_INTERVAL: invalid duration %q", envPrefix, v)
+		}
+		c.Sync.Interval = Duration(d)
+	}
+
+	if v := getenv(envPrefix + "PROVIDERS"); v != "" {
+		c.Providers = nil
+		for _, id := range splitList(v) {
+			c.Providers = append(c.Providers, ProviderConfig{ID: id})
+		}
+	}
+
+	return nil
+}
+
+// validate reports all problems at once so that a broken config file can be
+// fixed in one go.
+func (c Config) validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("  %s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		fail("server.port", "must be a number between 1 and 65535, got %q", c.Server.Port)
+	}
+
+	if c.Storage.Driver != "memory" {
+		fail("storage.driver", "unsupported driver %q (available: memory)", c.Storage.Driver)
+	}
+
+	if c.Sync.Interval < 0 {
+		fail("sync.interval", "must not be negative")
+	}
+	if c.Sync.Jitter < 0 {
+		fail("sync.jitter", "must not be negative")
+	}
+	if _, err := parseQuietHours(c.Sync.QuietHours); err != nil {
+		fail("sync.quiet_hours", "%v", err)
+	}
+	if c.Sync.Retry.Attempts < 1 {
+		fail("sync.retry.attempts", "must be at least 1")
+	}
+	if c.Sync.Retry.InitialBackoff <= 0 {
+		fail("sync.retry.initial_backoff", "must be positive")
+	}
+	if c.Sync.Retry.MaxBackoff < c.Sync.Retry.InitialBackoff {
+		fail("sync.retry.max_backoff", "must not be less than initial_backoff")
+	}
+	if c.Sync.Breaker.Threshold < 1 {
+		fail("sync.breaker.threshold", "must be at least 1")
+	}
+	if c.Sync.Breaker.Cooldown <= 0 {
+		fail("sync.breaker.cooldown", "must be positive")
+	}
+
+	if c.HTTP.UserAgent == "" {
+		fail("http.user_agent", "must not be empty, websites should be able to identify us")
+	}
+	if c.HTTP.Timeout <= 0 {
+		fail("http.timeout", "must be positive")
+	}
+	if c.HTTP.MinInterval < 0 {
+		fail("http.min_interval", "must not be negative")
+	}
+	if c.HTTP.CacheMinTTL < 0 {
+		fail("http.cache_min_ttl", "must not be negative")
+	}
+
+	if len(c.Providers) == 0 {
+		fail("providers", "at least one provider must be enabled")
+	}
+	known := provider.IDs()
+	// providers are told apart by name, entries without one get the
+	// default name of their provider
+	names := make(map[string]int)
+	for i, pc := range c.Providers {
+		field := fmt.Sprintf("providers[%d]", i)
+		if !slices.Contains(known, pc.ID) {
+			fail(field+".id", "unknown provider %q (available: %s)", pc.ID, strings.Join(known, ", "))
+		}
+		key := "name " + pc.Name
+		if pc.Name == "" {
+			key = "id " + pc.ID
+		}
+		if j, ok := names[key]; ok {
+			fail(field+".name", "must differ from the name of providers[%d]", j)
+		} else {
+			names[key] = i
+		}
+		if pc.ID == "ical" && len(pc.Sources) == 0 {
+			fail(field+".sources", "ical provider needs at least one source")
+		}
+		if pc.BaseURL != "" && !isHTTPURL(pc.BaseURL) {
+			fail(field+".base_url", "must be an http(s) URL, got %q", pc.BaseURL)
+		}
+		if pc.Timeout < 0 {
+			fail(field+".timeout", "must not be negative")
+		}
+		if pc.Schedule != "" {
+			if interval, _, err := parseSchedule(pc.Schedule); err != nil {
+				fail(field+".schedule", "%v", err)
+			} else if interval < 0 {
+				fail(field+".schedule", "must not be negative")
+			}
+		}
+		if pc.MinInterval < 0 {
+			fail(field+".min_interval", "must not be negative")
+		}
+		if pc.Jitter < 0 {
+			fail(field+".jitter", "must not be negative")
+		}
+		if _, err := parseQuietHours(pc.QuietHours); err != nil {
+			fail(field+".quiet_hours", "%v", err)
+		}
+	}
+
+	for cinema, pc := range c.Prices {
+		if _, err := pc.prices(); err != nil {
+			fail(fmt.Sprintf("prices[%q]", cinema), "%v", err)
+		}
+	}
+
+	if _, err := c.dayRollover(); err != nil {
+		fail("day_rollover", "%v", err)
+	}
+
+	for name, cinemas := range c.CinemaGroups {
+		if len(cinemas) == 0 {
+			fail(fmt.Sprintf("cinema_groups[%q]", name), "must list at least one cinema")
+		}
+	}
+
+	for i, group := range c.TitleAliases {
+		if len(group) < 2 {
+			fail(fmt.Sprintf("title_aliases[%d]", i), "must list at least two titles")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c Config) print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		// fall back to the relative path
+		return path
+	}
+	return abs
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func splitList(list string) []string {
+	var items []string
+	for item := range strings.SplitSeq(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
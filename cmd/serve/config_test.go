@@ -0,0 +1,151 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "server:\n  host: 0.0.0.0\n  port: \"8000\"\nsync:\n  interval: 1h\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"KINO_SERVER_PORT":   "8001",
+		"KINO_SYNC_INTERVAL": "2h",
+	}
+	cfg, _, err := loadConfig([]string{"-config", path, "-sync-interval", "3h"}, func(k string) string {
+		return env[k]
+	})
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	if cfg.Server.Host != "0.0.0.0" {
+		t.Errorf("Server.Host = %q, want value from file", cfg.Server.Host)
+	}
+	if cfg.Server.Port != "8001" {
+		t.Errorf("Server.Port = %q, want value from environment", cfg.Server.Port)
+	}
+	if time.Duration(cfg.Sync.Interval) != 3*time.Hour {
+		t.Errorf("Sync.Interval = %v, want value from flag", time.Duration(cfg.Sync.Interval))
+	}
+}
+
+func TestLoadConfig_Validation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "server:\n  port: \"http\"\nstorage:\n  driver: sqlite\nproviders:\n  - id: nope\n  - id: ical\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err := loadConfig([]string{"-config", path}, func(string) string { return "" })
+	if err == nil {
+		t.Fatal("loadConfig() expected error")
+	}
+
+	for _, want := range []string{"server.port", "storage.driver", "providers[0].id", "providers[1].sources"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestLoadConfig_DuplicateProviderNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `providers:
+  - id: ical
+    sources: [a.ics]
+  - id: ical
+    sources: [b.ics]
+  - id: ical
+    name: Festival
+    sources: [c.ics]
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err := loadConfig([]string{"-config", path}, func(string) string { return "" })
+	if err == nil {
+		t.Fatal("loadConfig() expected error")
+	}
+	if !strings.Contains(err.Error(), "providers[1].name") {
+		t.Errorf("error %q does not mention providers[1].name", err)
+	}
+	if strings.Contains(err.Error(), "providers[2]") {
+		t.Errorf("error %q mentions the named provider", err)
+	}
+}
+
+func TestLoadConfig_ProvidersAndICalFlags(t *testing.T) {
+	cfg, _, err := loadConfig([]string{"-providers", "yorck", "-ical", "feed.ics"}, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	want := []ProviderConfig{
+		{ID: "yorck"},
+		{ID: "ical", Sources: []string{"feed.ics"}},
+	}
+	if !reflect.DeepEqual(cfg.Providers, want) {
+		t.Errorf("Providers = %+v, want %+v", cfg.Providers, want)
+	}
+}
+
+func TestLoadConfig_UnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  prot: \"8080\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := loadConfig([]string{"-config", path}, func(string) string { return "" }); err == nil {
+		t.Error("loadConfig() expected error for unknown field")
+	}
+}
+
+func TestLoadConfig_Prices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `prices:
+  Kino Babylon:
+    regular: 9.50
+    reduced: 7.5
+  Delphi LUX:
+    regular: 13.5
+    special: 9
+    special_days: [Dienstag]
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _, err := loadConfig([]string{"-config", path}, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	prices, err := defaultPrices(cfg.Prices)
+	if err != nil {
+		t.Fatalf("defaultPrices() error = %v", err)
+	}
+	want := map[string]domain.Prices{
+		"Kino Babylon": {Currency: "EUR", Regular: 950, Reduced: 750},
+		"Delphi LUX":   {Currency: "EUR", Regular: 1350, Special: 900, SpecialDays: []time.Weekday{time.Tuesday}},
+	}
+	if !reflect.DeepEqual(prices, want) {
+		t.Errorf("defaultPrices() = %+v, want %+v", prices, want)
+	}
+
+	cfg.Prices["Broken"] = PriceConfig{Regular: 9, Special: 5, SpecialDays: []string{"caturday"}}
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), `prices["Broken"]`) {
+		t.Errorf("validate() error = %v, want error for prices[\"Broken\"]", err)
+	}
+}
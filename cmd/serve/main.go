@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
 	"net/http"
 	"os"
@@ -12,22 +14,73 @@ import (
 	"github.com/PhilippReinke/kino-berlin/pkg/app"
 	"github.com/PhilippReinke/kino-berlin/pkg/delivery"
 	"github.com/PhilippReinke/kino-berlin/pkg/domain"
-	"github.com/PhilippReinke/kino-berlin/pkg/infra/provider"
 	"github.com/PhilippReinke/kino-berlin/pkg/infra/storage"
+	"github.com/PhilippReinke/kino-berlin/web"
 )
 
 func main() {
-	cfg := parseFlags()
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		if err := runCache(os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Cache: %v", err)
+		}
+		return
+	}
+
+	cfg, printConfig, err := loadConfig(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if printConfig {
+		if err := cfg.print(os.Stdout); err != nil {
+			log.Fatalf("Failed to print configuration: %v", err)
+		}
+		return
+	}
 
 	storage := storage.NewMemory()
-	babylon := provider.NewBabylon()
-	yorck := provider.NewYorck()
+
+	defaultSchedule := cfg.Sync.schedule()
+	providers, schedules, err := buildProviders(cfg.Providers, defaultSchedule, cfg.HTTP)
+	if err != nil {
+		log.Fatalf("Failed to create providers: %v", err)
+	}
+
+	prices, err := defaultPrices(cfg.Prices)
+	if err != nil {
+		log.Fatalf("Failed to read prices: %v", err)
+	}
+
+	// validated by loadConfig
+	rollover, _ := cfg.dayRollover()
+
+	titleAliases := domain.NewTitleAliases(cfg.TitleAliases)
+	storage.SetTitleAliases(titleAliases)
 
 	application := app.New(
 		storage,
-		[]domain.Provider{babylon, yorck},
+		providers,
 		app.Config{
-			SyncInterval: cfg.SyncInterval,
+			SyncInterval: defaultSchedule.Interval,
+			SyncJitter:   defaultSchedule.Jitter,
+			QuietHours:   defaultSchedule.QuietHours,
+			Schedules:    schedules,
+			Retry: app.RetryPolicy{
+				MaxAttempts:    cfg.Sync.Retry.Attempts,
+				InitialBackoff: time.Duration(cfg.Sync.Retry.InitialBackoff),
+				MaxBackoff:     time.Duration(cfg.Sync.Retry.MaxBackoff),
+			},
+			Breaker: app.BreakerConfig{
+				FailureThreshold: cfg.Sync.Breaker.Threshold,
+				Cooldown:         time.Duration(cfg.Sync.Breaker.Cooldown),
+			},
+			DefaultPrices: prices,
+			TitleAliases:  titleAliases,
+			CinemaGroups:  cfg.CinemaGroups,
+			CinemaDays:    domain.CinemaDays{Rollover: rollover},
 		},
 	)
 
@@ -35,16 +88,25 @@ func main() {
 		log.Fatalf("Failed to start background sync: %v", err)
 	}
 
+	templates, static := web.Templates, web.Static
+	if cfg.Server.Templates != "" {
+		templates = os.DirFS(cfg.Server.Templates)
+	}
+	if cfg.Server.Static != "" {
+		static = os.DirFS(cfg.Server.Static)
+	}
+
 	handler, err := delivery.NewHandler(
 		application,
-		cfg.TemplateDir,
-		cfg.StaticDir,
+		templates,
+		static,
+		cfg.Server.Templates != "",
 	)
 	if err != nil {
 		log.Fatalf("Failed to create handler: %v", err)
 	}
 
-	if err := runServer(cfg.Addr, handler, application); err != nil {
+	if err := runServer(cfg.Server.Addr(), handler, application); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+// PriceConfig holds the default ticket prices of a cinema in the main unit
+// of the currency, e.g. 9.5 for 9,50 €.
+type PriceConfig struct {
+	// Currency defaults to EUR.
+	Currency string  `yaml:"currency,omitempty"`
+	Regular  float64 `yaml:"regular"`
+	Reduced  float64 `yaml:"reduced,omitempty"`
+	// Special is the price on SpecialDays, e.g. the weekly cinema day.
+	Special     float64  `yaml:"special,omitempty"`
+	SpecialDays []string `yaml:"special_days,omitempty"`
+}
+
+func (pc PriceConfig) prices() (domain.Prices, error) {
+	if pc.Regular < 0 || pc.Reduced < 0 || pc.Special < 0 {
+		return domain.Prices{}, fmt.Errorf("prices must not be negative")
+	}
+	if pc.Special > 0 && len(pc.SpecialDays) == 0 {
+		return domain.Prices{}, fmt.Errorf("special price needs special_days")
+	}
+
+	prices := domain.Prices{
+		Currency: pc.Currency,
+		Regular:  domain.MoneyFromFloat(pc.Regular),
+		Reduced:  domain.MoneyFromFloat(pc.Reduced),
+		Special:  domain.MoneyFromFloat(pc.Special),
+	}
+	if prices.Currency == "" {
+		prices.Currency = "EUR"
+	}
+	for _, name := range pc.SpecialDays {
+		day, err := domain.ParseWeekday(name)
+		if err != nil {
+			return domain.Prices{}, err
+		}
+		prices.SpecialDays = append(prices.SpecialDays, day)
+	}
+
+	return prices, nil
+}
+
+// defaultPrices converts the price table keyed by cinema name.
+func defaultPrices(configs map[string]PriceConfig) (map[string]domain.Prices, error) {
+	prices := make(map[string]domain.Prices, len(configs))
+	for cinema, pc := range configs {
+		p, err := pc.prices()
+		if err != nil {
+			return nil, fmt.Errorf("cinema %q: %w", cinema, err)
+		}
+		prices[cinema] = p
+	}
+	return prices, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/app"
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/provider"
+)
+
+// ProviderConfig enables a registered provider and sets its options.
+type ProviderConfig struct {
+	ID string `yaml:"id"`
+	// Name overrides the display name, providers must have distinct names.
+	Name      string   `yaml:"name,omitempty"`
+	BaseURL   string   `yaml:"base_url,omitempty"`
+	Timeout   Duration `yaml:"timeout,omitempty"`
+	UserAgent string   `yaml:"user_agent,omitempty"`
+	// MinInterval overrides http.min_interval for this provider.
+	MinInterval Duration `yaml:"min_interval,omitempty"`
+	Sources     []string `yaml:"sources,omitempty"`
+	// Schedule is either an interval like "6h" or a cron expression like
+	// "0 6 * * *". If empty, sync.interval applies.
+	Schedule string `yaml:"schedule,omitempty"`
+	// Jitter and QuietHours override the sync section if set.
+	Jitter     Duration `yaml:"jitter,omitempty"`
+	QuietHours []string `yaml:"quiet_hours,omitempty"`
+}
+
+// hasSchedule reports whether the provider overrides any scheduling option.
+func (pc ProviderConfig) hasSchedule() bool {
+	return pc.Schedule != "" || pc.Jitter > 0 || len(pc.QuietHours) > 0
+}
+
+// parseSchedule parses an interval or a cron expression.
+func parseSchedule(s string) (time.Duration, *app.Cron, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil, nil
+	}
+
+	c, err := app.ParseCron(s)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%q is neither a duration like \"6h\" nor a valid cron expression: %w", s, err)
+	}
+
+	return 0, c, nil
+}
+
+func parseQuietHours(windows []string) ([]app.TimeWindow, error) {
+	var parsed []app.TimeWindow
+	for _, w := range windows {
+		tw, err := app.ParseTimeWindow(w)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, tw)
+	}
+	return parsed, nil
+}
+
+// buildProviders creates the configured providers and returns them together
+// with their schedules keyed by provider name. Names must be unique. Providers without own
+// scheduling or HTTP options use the defaults from the sync and http
+// sections.
+func buildProviders(configs []ProviderConfig, defaults app.Schedule, httpDefaults HTTPConfig) ([]domain.Provider, map[string]app.Schedule, error) {
+	providers := make([]domain.Provider, 0, len(configs))
+	schedules := make(map[string]app.Schedule)
+	cache := httpDefaults.cache()
+	names := make(map[string]bool)
+
+	for _, pc := range configs {
+		opts := provider.Options{
+			Name:         pc.Name,
+			BaseURL:      pc.BaseURL,
+			Timeout:      time.Duration(httpDefaults.Timeout),
+			UserAgent:    httpDefaults.UserAgent,
+			MinInterval:  time.Duration(httpDefaults.MinInterval),
+			IgnoreRobots: httpDefaults.IgnoreRobots,
+			Cache:        cache,
+			Sources:      pc.Sources,
+		}
+		if pc.Timeout > 0 {
+			opts.Timeout = time.Duration(pc.Timeout)
+		}
+		if pc.UserAgent != "" {
+			opts.UserAgent = pc.UserAgent
+		}
+		if pc.MinInterval > 0 {
+			opts.MinInterval = time.Duration(pc.MinInterval)
+		}
+
+		p, err := provider.New(pc.ID, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if names[p.Name()] {
+			return nil, nil, fmt.Errorf("provider %q: name %q is already used by another provider, set a distinct name", pc.ID, p.Name())
+		}
+		names[p.Name()] = true
+		providers = append(providers, p)
+
+		if !pc.hasSchedule() {
+			continue
+		}
+
+		schedule := defaults
+		if pc.Schedule != "" {
+			schedule.Interval, schedule.Cron, err = parseSchedule(pc.Schedule)
+			if err != nil {
+				return nil, nil, fmt.Errorf("provider %q: %w", pc.ID, err)
+			}
+		}
+		if pc.Jitter > 0 {
+			schedule.Jitter = time.Duration(pc.Jitter)
+		}
+		if len(pc.QuietHours) > 0 {
+			schedule.QuietHours, err = parseQuietHours(pc.QuietHours)
+			if err != nil {
+				return nil, nil, fmt.Errorf("provider %q: %w", pc.ID, err)
+			}
+		}
+		schedules[p.Name()] = schedule
+	}
+
+	return providers, schedules, nil
+}
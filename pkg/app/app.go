@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -13,23 +14,53 @@ type App struct {
 	storage   domain.Storage
 	providers []domain.Provider
 
+	defaultPrices map[string]domain.Prices
+	titleAliases  *domain.TitleAliases
+	cinemaGroups  domain.CinemaGroups
+	cinemaDays    domain.CinemaDays
+
+	// resilience
+	retry  RetryPolicy
+	states map[string]*providerState
+
 	// sync management
-	syncInterval time.Duration
-	syncCtx      context.Context
-	syncCancel   context.CancelFunc
-	syncWg       sync.WaitGroup
-	syncMu       sync.RWMutex
-	syncRunning  bool
+	defaultSchedule Schedule
+	schedules       map[string]Schedule
+	syncCtx         context.Context
+	syncCancel      context.CancelFunc
+	syncWg          sync.WaitGroup
+	syncMu          sync.RWMutex
+	syncRunning     bool
 }
 
+// New creates the app. Schedules and the state of providers are kept by
+// provider name, so the names of providers must be unique.
 func New(storage domain.Storage, providers []domain.Provider, config Config) *App {
+	breakerConfig := config.Breaker.withDefaults()
+	states := make(map[string]*providerState, len(providers))
+	for _, p := range providers {
+		states[p.Name()] = &providerState{breaker: newBreaker(breakerConfig)}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	return &App{
-		storage:      storage,
-		providers:    providers,
-		syncInterval: config.SyncInterval,
-		syncCtx:      ctx,
-		syncCancel:   cancel,
+		storage:   storage,
+		providers: providers,
+
+		defaultPrices: config.DefaultPrices,
+		titleAliases:  config.TitleAliases,
+		cinemaGroups:  config.CinemaGroups,
+		cinemaDays:    config.CinemaDays,
+		retry:         config.Retry.withDefaults(),
+		states:        states,
+		defaultSchedule: Schedule{
+			Interval:   config.SyncInterval,
+			Jitter:     config.SyncJitter,
+			QuietHours: config.QuietHours,
+		},
+		schedules:  config.Schedules,
+		syncCtx:    ctx,
+		syncCancel: cancel,
 	}
 }
 
@@ -38,7 +69,7 @@ func (a *App) FetchScreenings(filters ...domain.Filter) ([]domain.Screening, err
 		return nil, fmt.Errorf("storage not configured")
 	}
 
-	screenings, err := a.storage.Fetch(filters...)
+	screenings, _, err := a.storage.Fetch(domain.Page{}, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("fetching screenings: %w", err)
 	}
@@ -46,6 +77,63 @@ func (a *App) FetchScreenings(filters ...domain.Filter) ([]domain.Screening, err
 	return screenings, nil
 }
 
+// FetchPage returns the page of the screenings matching all filters and the
+// number of matching screenings.
+func (a *App) FetchPage(page domain.Page, filters ...domain.Filter) ([]domain.Screening, int, error) {
+	if a.storage == nil {
+		return nil, 0, fmt.Errorf("storage not configured")
+	}
+
+	screenings, total, err := a.storage.Fetch(page, filters...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("fetching screenings: %w", err)
+	}
+
+	return screenings, total, nil
+}
+
+// SearchScreenings returns the page of the screenings matching query and all
+// filters, best matches first unless page has an order, and the number of
+// matching screenings.
+func (a *App) SearchScreenings(query string, page domain.Page, filters ...domain.Filter) ([]domain.Screening, int, error) {
+	if a.storage == nil {
+		return nil, 0, fmt.Errorf("storage not configured")
+	}
+
+	screenings, total, err := a.storage.Search(query, page, filters...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("searching screenings: %w", err)
+	}
+
+	return screenings, total, nil
+}
+
+// TitleFilter matches screenings whose title is similar to query, taking the
+// configured title aliases into account.
+func (a *App) TitleFilter(query string) domain.Filter {
+	return domain.TitleFilter(query, a.titleAliases)
+}
+
+// ParseQuery parses a filter expression, see domain.Query, taking the
+// configured title aliases, cinema groups and cinema days into account.
+func (a *App) ParseQuery(input string) (domain.Query, error) {
+	return domain.QueryParser{
+		TitleAliases: a.titleAliases,
+		CinemaGroups: a.cinemaGroups,
+		CinemaDays:   a.cinemaDays,
+	}.Parse(input)
+}
+
+// CinemaGroups returns the configured cinema groups.
+func (a *App) CinemaGroups() domain.CinemaGroups {
+	return a.cinemaGroups
+}
+
+// CinemaDays returns the configured cinema days.
+func (a *App) CinemaDays() domain.CinemaDays {
+	return a.cinemaDays
+}
+
 func (a *App) GetAvailableCinemas() ([]string, error) {
 	screenings, err := a.FetchScreenings(domain.ExpiredScreeningFilter())
 	if err != nil {
@@ -63,10 +151,13 @@ func (a *App) GetAvailableCinemas() ([]string, error) {
 	for cinema := range cinemaMap {
 		cinemas = append(cinemas, cinema)
 	}
+	slices.Sort(cinemas)
 
 	return cinemas, nil
 }
 
+// GetAvailableDates returns the cinema days of upcoming screenings, see
+// domain.CinemaDays, oldest first.
 func (a *App) GetAvailableDates() ([]time.Time, error) {
 	screenings, err := a.FetchScreenings(domain.ExpiredScreeningFilter())
 	if err != nil {
@@ -75,10 +166,8 @@ func (a *App) GetAvailableDates() ([]time.Time, error) {
 
 	dateMap := make(map[string]time.Time)
 	for _, s := range screenings {
-		year, month, day := s.Start.UTC().Date()
-		dateKey := fmt.Sprintf("%04d-%02d-%02d", year, month, day)
-		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
-		dateMap[dateKey] = date
+		date := a.cinemaDays.Day(s.Start)
+		dateMap[date.Format(time.DateOnly)] = date
 	}
 
 	dates := make([]time.Time, 0, len(dateMap))
@@ -97,3 +186,19 @@ func (a *App) GetAvailableDates() ([]time.Time, error) {
 
 	return dates, nil
 }
+
+// GetAvailableTags returns the tags of upcoming screenings in the order of
+// domain.AllTags.
+func (a *App) GetAvailableTags() ([]domain.Tag, error) {
+	screenings, err := a.FetchScreenings(domain.ExpiredScreeningFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	var tags domain.Tags
+	for _, s := range screenings {
+		tags = tags.With(s.Tags...)
+	}
+
+	return tags, nil
+}
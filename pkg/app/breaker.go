@@ -0,0 +1,132 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+type BreakerState string
+
+const (
+	// BreakerClosed lets every sync through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen skips syncs until the cooldown has passed.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single trial sync through after the cooldown.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerConfig configures the per-provider circuit breaker. Zero values fall
+// back to the defaults.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed syncs that open
+	// the breaker.
+	FailureThreshold int
+	// Cooldown is how long an open breaker skips syncs.
+	Cooldown time.Duration
+}
+
+func (c BreakerConfig) withDefaults() BreakerConfig {
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = 3
+	}
+	if c.Cooldown == 0 {
+		c.Cooldown = time.Hour
+	}
+	return c
+}
+
+// breaker stops syncing a provider whose source keeps failing.
+type breaker struct {
+	config BreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	// trial is set while the single sync let through by a half-open breaker
+	// runs.
+	trial bool
+}
+
+func newBreaker(config BreakerConfig) *breaker {
+	return &breaker{
+		config: config,
+		now:    time.Now,
+		state:  BreakerClosed,
+	}
+}
+
+// allow reports whether a sync may run. An open breaker turns half-open once
+// the cooldown has passed and then lets one trial through until its
+// outcome is recorded with success or failure.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.config.Cooldown {
+		b.state = BreakerHalfOpen
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+	}
+
+	return true
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.trial = false
+}
+
+// release ends a sync without an outcome, letting the next one through as
+// trial if the breaker is half-open.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+// failure records a failed sync and reports whether the breaker opened.
+func (b *breaker) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.state == BreakerHalfOpen || b.failures >= b.config.FailureThreshold {
+		opened := b.state != BreakerOpen
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+		return opened
+	}
+
+	return false
+}
+
+// snapshot returns the state and, if open, when the breaker will let the
+// next trial through.
+func (b *breaker) snapshot() (BreakerState, int, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var retryAt time.Time
+	if b.state == BreakerOpen {
+		retryAt = b.openedAt.Add(b.config.Cooldown)
+	}
+
+	return b.state, b.failures, retryAt
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker_HalfOpenSingleTrial(t *testing.T) {
+	b := newBreaker(BreakerConfig{FailureThreshold: 1, Cooldown: time.Hour})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	b.failure()
+	if b.allow() {
+		t.Fatal("allow() = true for open breaker")
+	}
+
+	now = now.Add(time.Hour)
+	if !b.allow() {
+		t.Fatal("allow() = false after cooldown, want trial")
+	}
+	if b.allow() {
+		t.Fatal("allow() = true while trial is running")
+	}
+
+	// a trial ended without outcome lets the next one through
+	b.release()
+	if !b.allow() {
+		t.Fatal("allow() = false after released trial, want trial")
+	}
+
+	// a failed trial opens the breaker for another cooldown
+	b.failure()
+	now = now.Add(time.Hour)
+	if !b.allow() {
+		t.Fatal("allow() = false after second cooldown, want trial")
+	}
+
+	b.success()
+	for range 2 {
+		if !b.allow() {
+			t.Fatal("allow() = false for closed breaker")
+		}
+	}
+}
@@ -1,9 +1,45 @@
 package app
 
-import "time"
+import (
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
 
 type Config struct {
 	// SyncInterval is the interval between automatic syncs from providers.
 	// If zero, background syncing is disabled.
 	SyncInterval time.Duration
+
+	// SyncJitter delays every sync by a random duration up to SyncJitter.
+	SyncJitter time.Duration
+
+	// QuietHours are windows in Europe/Berlin time during which providers
+	// are not scraped.
+	QuietHours []TimeWindow
+
+	// Schedules replaces the schedule built from the fields above for
+	// individual providers, keyed by provider name.
+	Schedules map[string]Schedule
+
+	// Retry configures retries of transient scrape failures.
+	Retry RetryPolicy
+
+	// Breaker configures the per-provider circuit breaker.
+	Breaker BreakerConfig
+
+	// DefaultPrices are used for screenings whose provider does not know
+	// the prices, keyed by cinema name.
+	DefaultPrices map[string]domain.Prices
+
+	// TitleAliases are alternative film titles used by TitleFilter.
+	// Optional.
+	TitleAliases *domain.TitleAliases
+
+	// CinemaGroups are named sets of cinemas to filter by. Optional.
+	CinemaGroups domain.CinemaGroups
+
+	// CinemaDays tells which day screenings are listed on. The zero value
+	// begins days at midnight.
+	CinemaDays domain.CinemaDays
 }
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+// RetryPolicy configures retries of scrapes that failed with a transient
+// error. Zero values fall back to the defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles with
+	// every further retry up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff == 0 {
+		p.InitialBackoff = 5 * time.Second
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = 2 * time.Minute
+	}
+	return p
+}
+
+// backoff returns the delay before the given retry (starting at 1). The delay
+// is drawn uniformly from [0, exponential backoff] ("full jitter") so that
+// retries of several instances do not line up.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxBackoff)
+
+	return rand.N(d + 1)
+}
+
+// isTransient reports whether err is likely to go away by retrying, i.e.
+// timeouts, connection resets and server side errors.
+func isTransient(err error) bool {
+	var httpErr *domain.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, os.ErrDeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// scrapeWithRetry scrapes provider and retries transient failures with
+// exponential backoff.
+func (a *App) scrapeWithRetry(ctx context.Context, provider domain.Provider) ([]domain.Screening, error) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		var screenings []domain.Screening
+		screenings, err = provider.Scrape()
+		if err == nil {
+			return screenings, nil
+		}
+
+		if !isTransient(err) || attempt >= a.retry.MaxAttempts {
+			return nil, err
+		}
+
+		delay := a.retry.backoff(attempt)
+		log.Printf("Scraping %q failed (attempt %d/%d), retrying in %v: %v",
+			provider.Name(), attempt, a.retry.MaxAttempts, delay.Round(time.Millisecond), err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
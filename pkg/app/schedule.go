@@ -0,0 +1,271 @@
+package app
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+// berlin is the time zone schedules and quiet hours are evaluated in.
+var berlin = domain.Berlin
+
+// Schedule decides when a provider is synced.
+type Schedule struct {
+	// Interval between syncs. Ignored if Cron is set.
+	Interval time.Duration
+	// Cron runs syncs at the times of a cron expression instead of a fixed
+	// interval.
+	Cron *Cron
+	// Jitter delays every sync by a random duration in [0, Jitter) so that
+	// providers are not all scraped at the same moment.
+	Jitter time.Duration
+	// QuietHours are windows during which the provider is not scraped.
+	QuietHours []TimeWindow
+}
+
+// Enabled reports whether the schedule triggers syncs at all.
+func (s Schedule) Enabled() bool {
+	return s.Cron != nil || s.Interval > 0
+}
+
+// Next returns the time of the next sync after the sync at last.
+func (s Schedule) Next(last time.Time) time.Time {
+	var next time.Time
+	if s.Cron != nil {
+		next = s.Cron.Next(last)
+	} else {
+		next = last.Add(s.Interval)
+	}
+
+	return s.delay(next)
+}
+
+// First returns the time of the initial sync when syncing starts at now.
+func (s Schedule) First(now time.Time) time.Time {
+	return s.delay(now)
+}
+
+// delay applies jitter and moves t out of quiet hours.
+func (s Schedule) delay(t time.Time) time.Time {
+	if s.Jitter > 0 {
+		t = t.Add(rand.N(s.Jitter))
+	}
+
+	// windows may overlap, so repeat until t is outside all of them
+	for moved := true; moved; {
+		moved = false
+		for _, w := range s.QuietHours {
+			if w.Contains(t) {
+				t = w.EndOf(t)
+				moved = true
+			}
+		}
+	}
+
+	return t
+}
+
+// TimeWindow is a daily window of wall-clock time in Europe/Berlin, e.g.
+// 01:00-07:00. Windows where End is before Start wrap around midnight.
+type TimeWindow struct {
+	Start time.Duration // offset since midnight
+	End   time.Duration // offset since midnight
+}
+
+// ParseTimeWindow parses windows like "01:00-07:00" or "23:30-06:00".
+func ParseTimeWindow(s string) (TimeWindow, error) {
+	startStr, endStr, ok := strings.Cut(s, "-")
+	if !ok {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q, want HH:MM-HH:MM", s)
+	}
+
+	start, err := domain.ParseClock(strings.TrimSpace(startStr))
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q: %w", s, err)
+	}
+	end, err := domain.ParseClock(strings.TrimSpace(endStr))
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q: %w", s, err)
+	}
+
+	return TimeWindow{Start: start, End: end}, nil
+}
+
+func (w TimeWindow) String() string {
+	format := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return format(w.Start) + "-" + format(w.End)
+}
+
+// Contains reports whether t lies within the window.
+func (w TimeWindow) Contains(t time.Time) bool {
+	offset := sinceMidnight(t)
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// EndOf returns the end of the window containing t.
+func (w TimeWindow) EndOf(t time.Time) time.Time {
+	local := t.In(berlin)
+	year, month, day := local.Date()
+	if w.Start > w.End && sinceMidnight(t) >= w.Start {
+		// the window ends tomorrow
+		day++
+	}
+	hour, minute := int(w.End.Hours()), int(w.End.Minutes())%60
+	return time.Date(year, month, day, hour, minute, 0, 0, berlin)
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	local := t.In(berlin)
+	return time.Duration(local.Hour())*time.Hour +
+		time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second
+}
+
+// Cron is a parsed five-field cron expression (minute, hour, day of month,
+// month, day of week) evaluated in Europe/Berlin.
+type Cron struct {
+	expr    string
+	minutes [60]bool
+	hours   [24]bool
+	days    [32]bool
+	months  [13]bool
+	weekday [7]bool
+	// per cron convention, if both day fields are restricted a day matches
+	// if either of them matches
+	anyDay, anyWeekday bool
+}
+
+// ParseCron parses expressions like "0 6 * * *" or "*/30 8-22 * * 1-5".
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", expr)
+	}
+
+	c := &Cron{expr: expr}
+	specs := []struct {
+		name     string
+		min, max int
+		set      func(int)
+	}{
+		{"minute", 0, 59, func(n int) { c.minutes[n] = true }},
+		{"hour", 0, 23, func(n int) { c.hours[n] = true }},
+		{"day of month", 1, 31, func(n int) { c.days[n] = true }},
+		{"month", 1, 12, func(n int) { c.months[n] = true }},
+		// 7 is an alias for Sunday
+		{"day of week", 0, 7, func(n int) { c.weekday[n%7] = true }},
+	}
+
+	for i, spec := range specs {
+		if err := parseCronField(fields[i], spec.min, spec.max, spec.set); err != nil {
+			return nil, fmt.Errorf("cron expression %q: %s: %w", expr, spec.name, err)
+		}
+	}
+	c.anyDay = fields[2] == "*"
+	c.anyWeekday = fields[4] == "*"
+
+	if c.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches", expr)
+	}
+
+	return c, nil
+}
+
+func parseCronField(field string, min, max int, set func(int)) error {
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			loStr, hiStr, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(loStr); err != nil {
+				return fmt.Errorf("invalid value %q", loStr)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return fmt.Errorf("invalid value %q", hiStr)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+
+		for n := lo; n <= hi; n += step {
+			set(n)
+		}
+	}
+
+	return nil
+}
+
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Next returns the first matching time strictly after t.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.In(berlin).Truncate(time.Minute).Add(time.Minute)
+
+	// bounded to a few years to guard against expressions that never match,
+	// like the 31st of February
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		switch {
+		case !c.months[month]:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, berlin)
+		case !c.matchesDay(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, berlin)
+		case !c.hours[t.Hour()]:
+			// adding an hour visits 02:00 twice at the end of daylight
+			// saving time, time.Date would only return the second
+			t = t.Add(time.Hour).Truncate(time.Hour)
+		case !c.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	day := c.days[t.Day()]
+	weekday := c.weekday[t.Weekday()]
+
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
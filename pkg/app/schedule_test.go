@@ -0,0 +1,86 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron_Next(t *testing.T) {
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"0 6 * * *", time.Date(2025, 3, 10, 5, 59, 0, 0, berlin), time.Date(2025, 3, 10, 6, 0, 0, 0, berlin)},
+		{"0 6 * * *", time.Date(2025, 3, 10, 6, 0, 0, 0, berlin), time.Date(2025, 3, 11, 6, 0, 0, 0, berlin)},
+		{"*/30 8-22 * * *", time.Date(2025, 3, 10, 22, 30, 0, 0, berlin), time.Date(2025, 3, 11, 8, 0, 0, 0, berlin)},
+		// Monday and Thursday
+		{"0 8 * * 1,4", time.Date(2025, 3, 11, 12, 0, 0, 0, berlin), time.Date(2025, 3, 13, 8, 0, 0, 0, berlin)},
+		// Sunday as 7
+		{"15 10 * * 7", time.Date(2025, 3, 10, 0, 0, 0, 0, berlin), time.Date(2025, 3, 16, 10, 15, 0, 0, berlin)},
+		{"0 0 1 * *", time.Date(2025, 1, 31, 12, 0, 0, 0, berlin), time.Date(2025, 2, 1, 0, 0, 0, 0, berlin)},
+		// evaluated in Berlin even if the input is UTC, across the start of
+		// daylight saving time on 30.03.2025
+		{"0 4 * * *", time.Date(2025, 3, 29, 12, 0, 0, 0, time.UTC), time.Date(2025, 3, 30, 4, 0, 0, 0, berlin)},
+		// 02:30 does not exist on 30.03.2025
+		{"30 2 * * *", time.Date(2025, 3, 29, 12, 0, 0, 0, berlin), time.Date(2025, 3, 31, 2, 30, 0, 0, berlin)},
+		// 02:00 to 03:00 comes twice on 26.10.2025, first in summer time
+		{"0 2 * * *", time.Date(2025, 10, 26, 1, 30, 0, 0, berlin), time.Date(2025, 10, 26, 0, 0, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2025, 10, 26, 0, 30, 0, 0, time.UTC), time.Date(2025, 10, 26, 1, 0, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, 10, 26, 1, 30, 0, 0, time.UTC), time.Date(2025, 10, 26, 2, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+		}
+		if got := c.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next(%v) = %v, want %v", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "0 0 31 2 *", "a * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected error", expr)
+		}
+	}
+}
+
+func TestSchedule_QuietHours(t *testing.T) {
+	night, err := ParseTimeWindow("23:00-06:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Schedule{Interval: time.Hour, QuietHours: []TimeWindow{night}}
+
+	tests := []struct {
+		last time.Time
+		want time.Time
+	}{
+		{time.Date(2025, 6, 1, 20, 0, 0, 0, berlin), time.Date(2025, 6, 1, 21, 0, 0, 0, berlin)},
+		{time.Date(2025, 6, 1, 22, 30, 0, 0, berlin), time.Date(2025, 6, 2, 6, 0, 0, 0, berlin)},
+		{time.Date(2025, 6, 2, 1, 0, 0, 0, berlin), time.Date(2025, 6, 2, 6, 0, 0, 0, berlin)},
+		{time.Date(2025, 6, 2, 5, 0, 0, 0, berlin), time.Date(2025, 6, 2, 6, 0, 0, 0, berlin)},
+	}
+
+	for _, tt := range tests {
+		if got := s.Next(tt.last); !got.Equal(tt.want) {
+			t.Errorf("Next(%v) = %v, want %v", tt.last, got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_Jitter(t *testing.T) {
+	s := Schedule{Interval: time.Hour, Jitter: 10 * time.Minute}
+	last := time.Date(2025, 6, 1, 12, 0, 0, 0, berlin)
+
+	for range 100 {
+		next := s.Next(last)
+		if next.Before(last.Add(time.Hour)) || !next.Before(last.Add(70*time.Minute)) {
+			t.Fatalf("Next() = %v, want within [13:00, 13:10)", next)
+		}
+	}
+}
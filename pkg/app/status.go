@@ -0,0 +1,75 @@
+package app
+
+import (
+	"sync"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+// ProviderStatus describes the sync state of a provider.
+type ProviderStatus struct {
+	Name        string
+	LastAttempt time.Time
+	LastSuccess time.Time
+	LastError   string
+	// Screenings is the number of screenings of the last successful sync.
+	Screenings int
+
+	Breaker             BreakerState
+	ConsecutiveFailures int
+	// BreakerRetryAt is when an open breaker lets the next sync through.
+	BreakerRetryAt time.Time
+}
+
+type providerState struct {
+	breaker *breaker
+
+	mu          sync.Mutex
+	lastAttempt time.Time
+	lastSuccess time.Time
+	lastError   string
+	screenings  int
+}
+
+func (s *providerState) record(at time.Time, screenings int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastAttempt = at
+	if err != nil {
+		s.lastError = err.Error()
+		return
+	}
+	s.lastSuccess = at
+	s.lastError = ""
+	s.screenings = screenings
+}
+
+func (a *App) state(provider domain.Provider) *providerState {
+	return a.states[provider.Name()]
+}
+
+// SyncStatus returns the sync state of all providers in configuration order.
+func (a *App) SyncStatus() []ProviderStatus {
+	statuses := make([]ProviderStatus, 0, len(a.providers))
+	for _, provider := range a.providers {
+		s := a.state(provider)
+		breakerState, failures, retryAt := s.breaker.snapshot()
+
+		s.mu.Lock()
+		statuses = append(statuses, ProviderStatus{
+			Name:                provider.Name(),
+			LastAttempt:         s.lastAttempt,
+			LastSuccess:         s.lastSuccess,
+			LastError:           s.lastError,
+			Screenings:          s.screenings,
+			Breaker:             breakerState,
+			ConsecutiveFailures: failures,
+			BreakerRetryAt:      retryAt,
+		})
+		s.mu.Unlock()
+	}
+
+	return statuses
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -10,11 +11,6 @@ import (
 )
 
 func (a *App) StartBackgroundSync() error {
-	if a.syncInterval == 0 {
-		// sync disabled
-		return nil
-	}
-
 	a.syncMu.Lock()
 	defer a.syncMu.Unlock()
 
@@ -22,35 +18,57 @@ func (a *App) StartBackgroundSync() error {
 		return fmt.Errorf("background sync already running")
 	}
 
-	a.syncRunning = true
-	a.syncWg.Add(1)
+	for _, provider := range a.providers {
+		schedule := a.scheduleFor(provider)
+		if !schedule.Enabled() {
+			// sync disabled
+			continue
+		}
 
-	a.syncWg.Go(func() {
-		defer a.syncWg.Done()
-		ticker := time.NewTicker(a.syncInterval)
-		defer ticker.Stop()
+		a.syncRunning = true
+		a.syncWg.Go(func() {
+			a.runProviderSync(provider, schedule)
+		})
+	}
 
-		// initial sync immediately
-		log.Printf("Starting background sync (interval: %v)", a.syncInterval)
-		if err := a.SyncFromProviders(a.syncCtx); err != nil {
-			log.Printf("Initial background sync failed: %v", err)
-		}
+	return nil
+}
+
+// scheduleFor returns the schedule of provider, falling back to the default
+// schedule if none is configured.
+func (a *App) scheduleFor(provider domain.Provider) Schedule {
+	if schedule, ok := a.schedules[provider.Name()]; ok {
+		return schedule
+	}
+	return a.defaultSchedule
+}
+
+func (a *App) runProviderSync(provider domain.Provider, schedule Schedule) {
+	next := schedule.First(time.Now())
+	log.Printf("Starting background sync of %q (first run: %v)", provider.Name(), next.Format(time.DateTime))
 
-		for {
-			select {
-			case <-a.syncCtx.Done():
-				log.Printf("Background sync stopped")
-				return
-			case <-ticker.C:
-				log.Printf("Running scheduled sync")
-				if err := a.SyncFromProviders(a.syncCtx); err != nil {
-					log.Printf("Background sync failed: %v", err)
-				}
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-a.syncCtx.Done():
+			log.Printf("Background sync of %q stopped", provider.Name())
+			return
+		case <-timer.C:
+			log.Printf("Running scheduled sync of %q", provider.Name())
+			if err := a.syncFromProvider(a.syncCtx, provider); err != nil {
+				log.Printf("Background sync of %q failed: %v", provider.Name(), err)
 			}
 		}
-	})
 
-	return nil
+		next = schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("Schedule of %q has no further runs", provider.Name())
+			return
+		}
+		timer.Reset(time.Until(next))
+	}
 }
 
 func (a *App) SyncFromProviders(ctx context.Context) error {
@@ -67,13 +85,34 @@ func (a *App) SyncFromProviders(ctx context.Context) error {
 	return nil
 }
 
+// errBreakerOpen is returned for syncs skipped by an open circuit breaker.
+var errBreakerOpen = errors.New("circuit breaker open")
+
 func (a *App) syncFromProvider(ctx context.Context, provider domain.Provider) error {
+	state := a.state(provider)
+	if !state.breaker.allow() {
+		return errBreakerOpen
+	}
+
 	log.Printf("Start scraping %q.", provider.Name())
 
-	screenings, err := provider.Scrape()
+	started := time.Now()
+	screenings, err := a.scrapeWithRetry(ctx, provider)
+	if errors.Is(err, context.Canceled) {
+		// stopped, e.g. on shutdown, which says nothing about the provider
+		state.breaker.release()
+		return err
+	}
 	if err != nil {
+		state.record(started, 0, err)
+		if state.breaker.failure() {
+			_, _, retryAt := state.breaker.snapshot()
+			log.Printf("Circuit breaker of %q opened, next attempt at %v", provider.Name(), retryAt.Format(time.DateTime))
+		}
 		return fmt.Errorf("scraping failed: %w", err)
 	}
+	state.record(started, len(screenings), nil)
+	state.breaker.success()
 
 	for _, screening := range screenings {
 		select {
@@ -82,6 +121,10 @@ func (a *App) syncFromProvider(ctx context.Context, provider domain.Provider) er
 		default:
 		}
 
+		if !screening.Prices.Known() {
+			screening.Prices = a.defaultPrices[screening.Cinema]
+		}
+
 		if err := a.storage.Upsert(screening); err != nil {
 			log.Printf("Failed to upsert screening %q: %v", screening.ID, err)
 		}
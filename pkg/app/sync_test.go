@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/storage"
+)
+
+type fakeProvider struct {
+	errs       []error
+	calls      int
+	screenings []domain.Screening
+}
+
+func (p *fakeProvider) Name() string {
+	return "Fake"
+}
+
+func (p *fakeProvider) Scrape() ([]domain.Screening, error) {
+	p.calls++
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.screenings != nil {
+		return p.screenings, nil
+	}
+	return []domain.Screening{{ID: "1", Title: "Film", UpdatedAt: time.Now()}}, nil
+}
+
+func newTestApp(p domain.Provider) *App {
+	return New(storage.NewMemory(), []domain.Provider{p}, Config{
+		Retry: RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+		},
+		Breaker: BreakerConfig{
+			FailureThreshold: 2,
+			Cooldown:         time.Hour,
+		},
+	})
+}
+
+func TestSync_RetriesTransientErrors(t *testing.T) {
+	p := &fakeProvider{errs: []error{
+		&domain.HTTPError{URL: "http://cinema", StatusCode: 503},
+		context.DeadlineExceeded,
+	}}
+	a := newTestApp(p)
+
+	if err := a.syncFromProvider(context.Background(), p); err != nil {
+		t.Fatalf("syncFromProvider() error = %v", err)
+	}
+	if p.calls != 3 {
+		t.Errorf("Scrape() called %d times, want 3", p.calls)
+	}
+
+	status := a.SyncStatus()[0]
+	if status.Screenings != 1 || status.LastError != "" || status.Breaker != BreakerClosed {
+		t.Errorf("SyncStatus() = %+v", status)
+	}
+}
+
+func TestSync_DoesNotRetryPermanentErrors(t *testing.T) {
+	p := &fakeProvider{errs: []error{&domain.HTTPError{URL: "http://cinema", StatusCode: 404}}}
+	a := newTestApp(p)
+
+	if err := a.syncFromProvider(context.Background(), p); err == nil {
+		t.Fatal("syncFromProvider() expected error")
+	}
+	if p.calls != 1 {
+		t.Errorf("Scrape() called %d times, want 1", p.calls)
+	}
+}
+
+func TestSync_CircuitBreaker(t *testing.T) {
+	permanent := errors.New("layout changed")
+	p := &fakeProvider{errs: []error{permanent, permanent, permanent}}
+	a := newTestApp(p)
+
+	now := time.Now()
+	a.state(p).breaker.now = func() time.Time { return now }
+
+	for range 2 {
+		if err := a.syncFromProvider(context.Background(), p); !errors.Is(err, permanent) {
+			t.Fatalf("syncFromProvider() error = %v, want %v", err, permanent)
+		}
+	}
+	if got := a.SyncStatus()[0].Breaker; got != BreakerOpen {
+		t.Fatalf("breaker = %q, want %q", got, BreakerOpen)
+	}
+
+	// open breaker skips syncs
+	if err := a.syncFromProvider(context.Background(), p); !errors.Is(err, errBreakerOpen) {
+		t.Fatalf("syncFromProvider() error = %v, want %v", err, errBreakerOpen)
+	}
+	if p.calls != 2 {
+		t.Errorf("Scrape() called %d times, want 2", p.calls)
+	}
+
+	// a failed trial after the cooldown opens the breaker again
+	now = now.Add(time.Hour)
+	if err := a.syncFromProvider(context.Background(), p); !errors.Is(err, permanent) {
+		t.Fatalf("syncFromProvider() error = %v, want %v", err, permanent)
+	}
+	if got := a.SyncStatus()[0].Breaker; got != BreakerOpen {
+		t.Fatalf("breaker = %q, want %q", got, BreakerOpen)
+	}
+
+	// a successful trial closes it
+	now = now.Add(time.Hour)
+	if err := a.syncFromProvider(context.Background(), p); err != nil {
+		t.Fatalf("syncFromProvider() error = %v", err)
+	}
+	if got := a.SyncStatus()[0].Breaker; got != BreakerClosed {
+		t.Errorf("breaker = %q, want %q", got, BreakerClosed)
+	}
+}
+
+func TestSync_CanceledDuringBackoff(t *testing.T) {
+	p := &fakeProvider{errs: []error{&domain.HTTPError{URL: "http://cinema", StatusCode: 503}}}
+	a := New(storage.NewMemory(), []domain.Provider{p}, Config{
+		Retry:   RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour},
+		Breaker: BreakerConfig{FailureThreshold: 1},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := a.syncFromProvider(ctx, p); !errors.Is(err, context.Canceled) {
+		t.Fatalf("syncFromProvider() error = %v, want %v", err, context.Canceled)
+	}
+
+	status := a.SyncStatus()[0]
+	if status.Breaker != BreakerClosed || status.ConsecutiveFailures != 0 {
+		t.Errorf("breaker = %q after %d failures, want %q after 0", status.Breaker, status.ConsecutiveFailures, BreakerClosed)
+	}
+	if !status.LastAttempt.IsZero() || status.LastError != "" {
+		t.Errorf("canceled sync recorded as attempt at %v with error %q", status.LastAttempt, status.LastError)
+	}
+}
+
+func TestSync_DefaultPrices(t *testing.T) {
+	p := &fakeProvider{screenings: []domain.Screening{
+		{ID: "1", Title: "Film", Cinema: "Fake Kino", UpdatedAt: time.Now()},
+		{ID: "2", Title: "Free Film", Cinema: "Fake Kino", Prices: domain.Prices{Currency: "EUR"}, UpdatedAt: time.Now()},
+	}}
+	defaults := domain.Prices{Currency: "EUR", Regular: 1000, Reduced: 800}
+	a := New(storage.NewMemory(), []domain.Provider{p}, Config{
+		DefaultPrices: map[string]domain.Prices{"Fake Kino": defaults},
+	})
+
+	if err := a.syncFromProvider(context.Background(), p); err != nil {
+		t.Fatalf("syncFromProvider() error = %v", err)
+	}
+
+	screenings, err := a.FetchScreenings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range screenings {
+		want := defaults
+		if s.ID == "2" {
+			// known prices are kept
+			want = domain.Prices{Currency: "EUR"}
+		}
+		if !reflect.DeepEqual(s.Prices, want) {
+			t.Errorf("prices of %q = %+v, want %+v", s.Title, s.Prices, want)
+		}
+	}
+}
+
+func TestGetAvailableDates_CinemaDays(t *testing.T) {
+	// a late show after midnight and an evening show, stored in UTC
+	now := time.Now()
+	evening := time.Date(now.Year()+1, 3, 7, 19, 0, 0, 0, time.UTC)
+	late := time.Date(now.Year()+1, 3, 7, 23, 30, 0, 0, time.UTC)
+	p := &fakeProvider{screenings: []domain.Screening{
+		{ID: "evening", Title: "Film", Start: evening, UpdatedAt: now},
+		{ID: "late", Title: "Late Film", Start: late, UpdatedAt: now},
+	}}
+	days := domain.CinemaDays{Rollover: 4 * time.Hour}
+	a := New(storage.NewMemory(), []domain.Provider{p}, Config{CinemaDays: days})
+	if err := a.syncFromProvider(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+
+	dates, err := a.GetAvailableDates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{time.Date(now.Year()+1, 3, 7, 0, 0, 0, 0, domain.Berlin)}
+	if !reflect.DeepEqual(dates, want) {
+		t.Fatalf("GetAvailableDates() = %v, want %v", dates, want)
+	}
+
+	// the date of the select finds both screenings
+	screenings, err := a.FetchScreenings(days.DateFilter(dates[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(screenings) != 2 {
+		t.Errorf("FetchScreenings(%v) returned %d screenings, want 2", dates[0], len(screenings))
+	}
+}
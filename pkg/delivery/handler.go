@@ -1,48 +1,135 @@
 package delivery
 
 import (
+	"encoding/json"
+	"errors"
 	"log"
+	"maps"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/PhilippReinke/kino-berlin/pkg/domain"
 )
 
 func (h *Handler) handleSelects(w http.ResponseWriter, r *http.Request) {
-	cinemas, err := h.app.GetAvailableCinemas()
+	tr := requestTranslator(w, r)
+	filters, err := h.filtersViewModel(r, tr)
 	if err != nil {
-		h.renderError(w, err)
+		h.renderError(w, tr, err)
 		return
 	}
 
-	dates, err := h.app.GetAvailableDates()
+	if err := h.renderFragment(w, tr, "selects", filters); err != nil {
+		h.renderError(w, tr, err)
+		return
+	}
+}
+
+func (h *Handler) handleScreenings(w http.ResponseWriter, r *http.Request) {
+	tr := requestTranslator(w, r)
+	results, err := h.resultsViewModel(r, tr)
 	if err != nil {
-		h.renderError(w, err)
+		h.renderError(w, tr, err)
+		return
+	}
+	results.Fragment = true
+
+	if err := h.renderFragment(w, tr, "results", results); err != nil {
+		h.renderError(w, tr, err)
 		return
 	}
+}
+
+// resultsViewModel returns the screenings matching r as list or, with the
+// parameter view=week, as week grid.
+func (h *Handler) resultsViewModel(r *http.Request, tr *translator) (ResultsViewModel, error) {
+	if r.FormValue("view") == "week" {
+		screenings, total, err := h.filteredScreenings(r, domain.Page{})
+		if err != nil {
+			return ResultsViewModel{}, err
+		}
+		days := h.app.CinemaDays()
+		week := newWeekViewModel(weekStart(r, days), weekRows(r), screenings, days, tr)
+		return ResultsViewModel{Total: total, Week: week}, nil
+	}
+
+	page := requestPage(r, pageSize)
+	screenings, total, err := h.filteredScreenings(r, page)
+	if err != nil {
+		return ResultsViewModel{}, err
+	}
 
-	data := struct {
-		ScrapeIDs []string
-		Cinemas   []string
-		Dates     []time.Time
-	}{
-		ScrapeIDs: []string{},
-		Cinemas:   cinemas,
-		Dates:     dates,
+	vm := ResultsViewModel{
+		Screenings: screeningViewModels(screenings, h.app.CinemaDays(), tr),
+		Total:      total,
+		Offset:     page.Offset,
 	}
+	if next := page.Offset + len(screenings); next < total {
+		query := maps.Clone(r.Form)
+		query.Set("offset", strconv.Itoa(next))
+		vm.More = &MoreViewModel{
+			URL:      "/?" + query.Encode(),
+			Fragment: "/api/screenings?" + query.Encode(),
+		}
+	}
+	return vm, nil
+}
+
+// pageSize is the number of screenings shown at once in the list.
+const pageSize = 50
 
-	if err := h.templates.ExecuteTemplate(w, "selects", data); err != nil {
-		h.renderError(w, err)
+// requestPage returns the page asked for by the parameters sort and offset of
+// r with the given limit.
+func requestPage(r *http.Request, limit int) domain.Page {
+	order, _ := domain.ParseOrder(r.FormValue("sort"))
+	offset, err := strconv.Atoi(r.FormValue("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return domain.Page{Order: order, Offset: offset, Limit: limit}
+}
+
+func (h *Handler) handleScreeningsJSON(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.FormValue("limit"))
+	if err != nil || limit < 0 {
+		limit = 0
+	}
+
+	screenings, total, err := h.filteredScreenings(r, requestPage(r, limit))
+	var parseErr *domain.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		log.Printf("Error: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+
+	viewModels := make([]ScreeningJSON, len(screenings))
+	for i, s := range screenings {
+		viewModels[i] = newScreeningJSON(s, h.app.CinemaDays())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if err := json.NewEncoder(w).Encode(viewModels); err != nil {
+		log.Printf("Error: encoding screenings: %v", err)
+	}
 }
 
-func (h *Handler) handleScreenings(w http.ResponseWriter, r *http.Request) {
+// filteredScreenings returns the page of the screenings matching the filters
+// of the form or query of r and the number of matching screenings.
+func (h *Handler) filteredScreenings(r *http.Request, page domain.Page) ([]domain.Screening, int, error) {
 	if err := r.ParseForm(); err != nil {
-		h.renderError(w, err)
-		return
+		return nil, 0, err
 	}
 
+	cinemaDays := h.app.CinemaDays()
 	filters := []domain.Filter{
 		domain.ExpiredFilter(47 * time.Hour),
 		domain.ExpiredScreeningFilter(),
@@ -50,41 +137,320 @@ func (h *Handler) handleScreenings(w http.ResponseWriter, r *http.Request) {
 
 	if dateStr := r.FormValue("dates"); dateStr != "" {
 		if date, err := time.Parse(time.DateOnly, dateStr); err == nil {
-			filters = append(filters, domain.DateFilter(date))
+			filters = append(filters, dateFilter(cinemaDays, date, r.FormValue("date_to")))
 		}
 	}
 
-	if cinema := r.FormValue("cinemas"); cinema != "" {
-		filters = append(filters, domain.CinemaFilter(cinema))
+	if r.FormValue("view") == "week" {
+		start := weekStart(r, cinemaDays)
+		filters = append(filters, cinemaDays.DateRangeFilter(start, start.AddDate(0, 0, 6)))
 	}
 
-	screenings, err := h.app.FetchScreenings(filters...)
-	if err != nil {
-		h.renderError(w, err)
-		return
+	if period, ok := domain.ParsePeriod(r.FormValue("period")); ok {
+		filters = append(filters, cinemaDays.PeriodFilter(period, time.Now()))
+	}
+
+	if f, ok := timeOfDayFilter(cinemaDays, r.FormValue("after"), r.FormValue("before")); ok {
+		filters = append(filters, f)
+	}
+
+	if f, ok := durationFilter(r.FormValue("min_runtime"), r.FormValue("max_runtime")); ok {
+		filters = append(filters, f)
+	}
+
+	if endsBefore, err := domain.ParseClock(r.FormValue("ends_before")); err == nil {
+		filters = append(filters, cinemaDays.EndsBeforeFilter(endsBefore))
+	}
+
+	var days []time.Weekday
+	for _, name := range r.Form["weekdays"] {
+		if day, err := domain.ParseWeekday(name); err == nil {
+			days = append(days, day)
+		}
+	}
+	if len(days) > 0 {
+		filters = append(filters, cinemaDays.WeekdayFilter(days...))
+	}
+
+	var cinemas []string
+	for _, cinema := range r.Form["cinemas"] {
+		if cinema != "" {
+			cinemas = append(cinemas, cinema)
+		}
+	}
+	groups := slices.DeleteFunc(slices.Clone(r.Form["groups"]), func(g string) bool { return g == "" })
+	if len(cinemas) > 0 || len(groups) > 0 {
+		cinemas = append(cinemas, h.app.CinemaGroups().Expand(groups...)...)
+		filters = append(filters, domain.CinemaFilter(cinemas...))
+	}
+
+	var tags []domain.Tag
+	for _, name := range r.Form["tags"] {
+		if tag, ok := domain.ParseTag(name); ok {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		filters = append(filters, domain.TagFilter(tags...))
+	}
+
+	if r.FormValue("hide_sold_out") != "" {
+		filters = append(filters, domain.AvailableFilter())
+	}
+
+	if maxPrice := r.FormValue("max_price"); maxPrice != "" {
+		if max, err := domain.ParseMoney(maxPrice); err == nil {
+			filters = append(filters, domain.MaxPriceFilter(max))
+		}
+	}
+
+	if title := strings.TrimSpace(r.FormValue("title")); title != "" {
+		filters = append(filters, h.app.TitleFilter(title))
 	}
 
+	if input := strings.TrimSpace(r.FormValue("q")); input != "" {
+		query, err := h.app.ParseQuery(input)
+		if err != nil {
+			return nil, 0, err
+		}
+		filters = append(filters, query.Filter)
+		if query.Text != "" {
+			return h.app.SearchScreenings(query.Text, page, filters...)
+		}
+	}
+
+	return h.app.FetchPage(page, filters...)
+}
+
+func (h *Handler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	statuses := h.app.SyncStatus()
+
+	viewModels := make([]ProviderStatusViewModel, len(statuses))
+	for i, s := range statuses {
+		viewModels[i] = ProviderStatusViewModel{
+			Name:                s.Name,
+			LastAttempt:         optionalTime(s.LastAttempt),
+			LastSuccess:         optionalTime(s.LastSuccess),
+			LastError:           s.LastError,
+			Screenings:          s.Screenings,
+			Breaker:             string(s.Breaker),
+			ConsecutiveFailures: s.ConsecutiveFailures,
+			BreakerRetryAt:      optionalTime(s.BreakerRetryAt),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(viewModels); err != nil {
+		log.Printf("Error: encoding status: %v", err)
+	}
+}
+
+func screeningViewModels(screenings []domain.Screening, days domain.CinemaDays, tr *translator) []ScreeningViewModel {
 	viewModels := make([]ScreeningViewModel, len(screenings))
 	for i, s := range screenings {
 		viewModels[i] = ScreeningViewModel{
 			Title:         s.Title,
 			Cinema:        s.Cinema,
 			Duration:      int(s.Duration.Minutes()),
-			Date:          s.Start,
+			Date:          s.Start.In(domain.Berlin),
+			Day:           days.Day(s.Start),
+			End:           endText(s),
+			Language:      s.Language,
+			Director:      s.Director,
+			Cast:          s.Cast,
+			Facts:         screeningFacts(s),
+			Tags:          tagLabels(s.Tags, tr),
+			Year:          s.Year,
+			Country:       s.Country,
+			Notes:         s.Notes,
+			Price:         priceText(s.Prices, s.Start, tr),
 			Link:          s.Links.Details,
+			BookingLink:   s.Links.Booking,
 			ThumbnailLink: s.Links.ThumbnailLink,
 		}
+		viewModels[i].Availability, viewModels[i].AvailabilityClass = availabilityBadge(s.Availability, tr)
 	}
+	return viewModels
+}
 
-	if err := h.templates.ExecuteTemplate(w, "screenings", viewModels); err != nil {
-		h.renderError(w, err)
-		return
+// weekdays lists the days of the week starting on Monday.
+var weekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+// dateFilter matches screenings on date or, if to is a later date, on the
+// days from date to to.
+func dateFilter(days domain.CinemaDays, date time.Time, to string) domain.Filter {
+	if last, err := time.Parse(time.DateOnly, to); err == nil && last.After(date) {
+		return days.DateRangeFilter(date, last)
+	}
+	return days.DateFilter(date)
+}
+
+// timeOfDayFilter returns a filter for screenings starting between after and
+// before, given as "HH:MM". Either may be empty, ok is false if both are.
+func timeOfDayFilter(days domain.CinemaDays, after, before string) (f domain.Filter, ok bool) {
+	from, errFrom := domain.ParseClock(after)
+	to, errTo := domain.ParseClock(before)
+	switch {
+	case errFrom == nil && errTo == nil:
+		return days.StartsBetweenFilter(from, to), true
+	case errFrom == nil:
+		return days.StartsAfterFilter(from), true
+	case errTo == nil:
+		return days.StartsBeforeFilter(to), true
+	default:
+		return nil, false
+	}
+}
+
+// durationFilter returns a filter for screenings lasting between min and max,
+// given as runtimes like "100". Either may be empty, ok is false if both are.
+func durationFilter(min, max string) (f domain.Filter, ok bool) {
+	minRuntime, errMin := domain.ParseRuntime(min)
+	maxRuntime, errMax := domain.ParseRuntime(max)
+	if errMin != nil && errMax != nil {
+		return nil, false
 	}
+	return domain.DurationFilter(minRuntime, maxRuntime), true
 }
 
-func (h *Handler) renderError(w http.ResponseWriter, err error) {
-	log.Printf("Error: %v", err)
-	if err := h.templates.ExecuteTemplate(w, "error", err.Error()); err != nil {
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func (h *Handler) renderError(w http.ResponseWriter, tr *translator, err error) {
+	// invalid queries are the user's mistake, not worth logging
+	var parseErr *domain.ParseError
+	if !errors.As(err, &parseErr) {
+		log.Printf("Error: %v", err)
+	}
+	if err := h.renderFragment(w, tr, "error", err.Error()); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
+
+func screeningFacts(s domain.Screening) []string {
+	facts := slices.Clone(s.Genres)
+	for _, fact := range []string{s.AgeRating, s.Format} {
+		if fact != "" {
+			facts = append(facts, fact)
+		}
+	}
+	return facts
+}
+
+func availabilityBadge(a domain.Availability, tr *translator) (label, class string) {
+	switch a {
+	case domain.AvailabilityAvailable:
+		return tr.T("availability.available"), "available"
+	case domain.AvailabilityFewSeats:
+		return tr.T("availability.few_seats"), "few-seats"
+	case domain.AvailabilitySoldOut:
+		return tr.T("availability.sold_out"), "sold-out"
+	default:
+		return "", ""
+	}
+}
+
+// endText returns the end time of s like "21:45", empty if the duration is
+// unknown.
+func endText(s domain.Screening) string {
+	end, known := s.End()
+	if !known {
+		return ""
+	}
+	return end.In(domain.Berlin).Format("15:04")
+}
+
+func tagLabels(tags domain.Tags, tr *translator) []string {
+	labels := make([]string, len(tags))
+	for i, t := range tags {
+		labels[i] = tr.label("tag."+string(t), t.Label())
+	}
+	return labels
+}
+
+// priceText formats the prices of a screening starting at start, e.g.
+// "9.50 €, reduced 7.50 €".
+func priceText(p domain.Prices, start time.Time, tr *translator) string {
+	if !p.Known() {
+		return ""
+	}
+
+	price := p.On(start)
+	if price == 0 {
+		return tr.T("price.free")
+	}
+
+	text := tr.Money(price, p.Currency)
+	if price < p.Regular {
+		text += " " + tr.T("price.cinema_day")
+	}
+	if p.Reduced > 0 && p.Reduced < price {
+		text += ", " + tr.T("price.reduced", tr.Money(p.Reduced, p.Currency))
+	}
+	return text
+}
+
+func newScreeningJSON(s domain.Screening, days domain.CinemaDays) ScreeningJSON {
+	var end *time.Time
+	if t, known := s.End(); known {
+		end = &t
+	}
+
+	tags := make([]string, len(s.Tags))
+	for i, t := range s.Tags {
+		tags[i] = string(t)
+	}
+
+	var prices *PriceJSON
+	if s.Prices.Known() {
+		prices = &PriceJSON{
+			Currency: s.Prices.Currency,
+			Regular:  s.Prices.Regular.String(),
+			OnDay:    s.Prices.On(s.Start).String(),
+		}
+		if s.Prices.Reduced > 0 {
+			prices.Reduced = s.Prices.Reduced.String()
+		}
+		if s.Prices.Special > 0 {
+			prices.Special = s.Prices.Special.String()
+			for _, day := range s.Prices.SpecialDays {
+				prices.SpecialDays = append(prices.SpecialDays, strings.ToLower(day.String()))
+			}
+		}
+	}
+
+	return ScreeningJSON{
+		ID:           string(s.ID),
+		Title:        s.Title,
+		Description:  s.Description,
+		Start:        s.Start,
+		Day:          days.Day(s.Start).Format(time.DateOnly),
+		End:          end,
+		Duration:     int(s.Duration.Minutes()),
+		Cinema:       s.Cinema,
+		Language:     s.Language,
+		Director:     s.Director,
+		Cast:         s.Cast,
+		Genres:       s.Genres,
+		AgeRating:    s.AgeRating,
+		Year:         s.Year,
+		Country:      s.Country,
+		Notes:        s.Notes,
+		Format:       s.Format,
+		Tags:         tags,
+		Availability: s.Availability.String(),
+		Prices:       prices,
+		Links: LinksJSON{
+			Details:   s.Links.Details,
+			Booking:   s.Links.Booking,
+			Thumbnail: s.Links.ThumbnailLink,
+		},
+	}
+}
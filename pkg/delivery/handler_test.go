@@ -0,0 +1,64 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/app"
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+// failingStorage fails every read.
+type failingStorage struct{}
+
+func (failingStorage) Upsert(domain.Screening) error { return nil }
+
+func (failingStorage) Fetch(domain.Page, ...domain.Filter) ([]domain.Screening, int, error) {
+	return nil, 0, errors.New("disk on fire")
+}
+
+func (failingStorage) Search(string, domain.Page, ...domain.Filter) ([]domain.Screening, int, error) {
+	return nil, 0, errors.New("disk on fire")
+}
+
+func TestNewScreeningJSON_Availability(t *testing.T) {
+	tests := []struct {
+		availability domain.Availability
+		want         string
+	}{
+		{domain.AvailabilityUnknown, "unknown"},
+		{domain.AvailabilityAvailable, "available"},
+		{domain.AvailabilityFewSeats, "few-seats"},
+		{domain.AvailabilitySoldOut, "sold-out"},
+	}
+
+	for _, tt := range tests {
+		got := newScreeningJSON(domain.Screening{Availability: tt.availability}, domain.CinemaDays{})
+		if got.Availability != tt.want {
+			t.Errorf("availability %d = %q, want %q", tt.availability, got.Availability, tt.want)
+		}
+	}
+}
+
+func TestHandleScreeningsJSON_Errors(t *testing.T) {
+	h := &Handler{app: app.New(failingStorage{}, nil, app.Config{})}
+
+	tests := []struct {
+		url  string
+		want int
+	}{
+		{"/api/screenings.json?q=%22metropolis", http.StatusBadRequest},
+		{"/api/screenings.json?q=metropolis", http.StatusInternalServerError},
+		{"/api/screenings.json", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h.handleScreeningsJSON(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		if w.Code != tt.want {
+			t.Errorf("GET %s status = %d, want %d", tt.url, w.Code, tt.want)
+		}
+	}
+}
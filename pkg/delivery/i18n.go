@@ -0,0 +1,194 @@
+package delivery
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+// languages lists the languages of the UI, the first is the fallback.
+var languages = []language.Tag{language.English, language.German}
+
+var languageMatcher = language.NewMatcher(languages)
+
+// languageNames are the names of the languages in themselves, for the
+// language toggle.
+var languageNames = map[language.Tag]string{
+	language.English: "English",
+	language.German:  "Deutsch",
+}
+
+// languageCookie remembers the language picked with the toggle.
+const languageCookie = "lang"
+
+// requestLanguage returns the language of the UI for r: the lang parameter
+// of the toggle, else the language picked before, else the best match for
+// Accept-Language.
+func requestLanguage(r *http.Request) language.Tag {
+	var picked string
+	if cookie, err := r.Cookie(languageCookie); err == nil {
+		picked = cookie.Value
+	}
+	tag, _ := language.MatchStrings(languageMatcher, r.URL.Query().Get("lang"), picked, r.Header.Get("Accept-Language"))
+	base, _ := tag.Base()
+	for _, l := range languages {
+		if b, _ := l.Base(); b == base {
+			return l
+		}
+	}
+	return languages[0]
+}
+
+// requestTranslator returns the translator for the language of r. A
+// language picked with the toggle is kept in a cookie.
+func requestTranslator(w http.ResponseWriter, r *http.Request) *translator {
+	lang := requestLanguage(r)
+	if r.URL.Query().Get("lang") != "" {
+		http.SetCookie(w, &http.Cookie{
+			Name:     languageCookie,
+			Value:    lang.String(),
+			Path:     "/",
+			MaxAge:   365 * 24 * 60 * 60,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	w.Header().Add("Vary", "Accept-Language, Cookie")
+	return translatorFor(lang)
+}
+
+// translator translates messages and formats dates for a language.
+type translator struct {
+	lang     language.Tag
+	messages map[string]string
+	formats  formats
+}
+
+type formats struct {
+	// date and dayMonth are layouts for time.Format.
+	date, dayMonth string
+	// weekdays and shortWeekdays are indexed by time.Weekday, nil means
+	// the English names of time.
+	weekdays, shortWeekdays []string
+	// decimalComma separates decimals with a comma instead of a point.
+	decimalComma bool
+}
+
+var translators = map[language.Tag]*translator{
+	language.English: {
+		lang:     language.English,
+		messages: messagesEN,
+		formats: formats{
+			date:     "Jan 2, 2006",
+			dayMonth: "Jan 2",
+		},
+	},
+	language.German: {
+		lang:     language.German,
+		messages: messagesDE,
+		formats: formats{
+			date:          "02.01.2006",
+			dayMonth:      "02.01.",
+			weekdays:      []string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			shortWeekdays: []string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+			decimalComma:  true,
+		},
+	},
+}
+
+func translatorFor(lang language.Tag) *translator {
+	if t, ok := translators[lang]; ok {
+		return t
+	}
+	return translators[languages[0]]
+}
+
+// T returns the message key formatted with args like fmt.Sprintf. Messages
+// missing in the language are taken from English, unknown keys returned as
+// they are.
+func (t *translator) T(key string, args ...any) string {
+	msg, ok := t.messages[key]
+	if !ok {
+		if msg, ok = messagesEN[key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N returns the message key + ".one" for n == 1 and key + ".other"
+// otherwise, formatted with n.
+func (t *translator) N(key string, n int) string {
+	if n == 1 {
+		return t.T(key+".one", n)
+	}
+	return t.T(key+".other", n)
+}
+
+// label returns the message key if the language has one, fallback
+// otherwise. It translates names with English labels in the domain.
+func (t *translator) label(key, fallback string) string {
+	if msg, ok := t.messages[key]; ok {
+		return msg
+	}
+	return fallback
+}
+
+func (t *translator) Date(d time.Time) string {
+	return d.Format(t.formats.date)
+}
+
+func (t *translator) DayMonth(d time.Time) string {
+	return d.Format(t.formats.dayMonth)
+}
+
+func (t *translator) Weekday(d time.Weekday) string {
+	if t.formats.weekdays == nil {
+		return d.String()
+	}
+	return t.formats.weekdays[d]
+}
+
+func (t *translator) ShortWeekday(d time.Weekday) string {
+	if t.formats.shortWeekdays == nil {
+		return d.String()[:3]
+	}
+	return t.formats.shortWeekdays[d]
+}
+
+// Money formats m like "9.50 €".
+func (t *translator) Money(m domain.Money, currency string) string {
+	amount := m.String()
+	if t.formats.decimalComma {
+		amount = strings.Replace(amount, ".", ",", 1)
+	}
+	if currency == "EUR" {
+		return amount + " €"
+	}
+	return amount + " " + currency
+}
+
+// funcs returns the template functions of the language.
+func (t *translator) funcs() template.FuncMap {
+	return template.FuncMap{
+		"lang":     t.lang.String,
+		"t":        t.T,
+		"tn":       t.N,
+		"date":     t.Date,
+		"dayMonth": t.DayMonth,
+		"weekday": func(d time.Time) string {
+			return t.Weekday(d.Weekday())
+		},
+		"shortWeekday": func(d time.Time) string {
+			return t.ShortWeekday(d.Weekday())
+		},
+	}
+}
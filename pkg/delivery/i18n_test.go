@@ -0,0 +1,78 @@
+package delivery
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestMessages(t *testing.T) {
+	for key := range messagesEN {
+		if _, ok := messagesDE[key]; !ok {
+			t.Errorf("German message %q missing", key)
+		}
+	}
+	for key := range messagesDE {
+		// names of the domain have English labels there
+		domainName := strings.HasPrefix(key, "order.") && key != "order.default" ||
+			strings.HasPrefix(key, "period.") || strings.HasPrefix(key, "tag.")
+		if _, ok := messagesEN[key]; !ok && !domainName {
+			t.Errorf("English message %q missing", key)
+		}
+	}
+}
+
+func TestRequestLanguage(t *testing.T) {
+	tests := []struct {
+		url, cookie, accept string
+		want                language.Tag
+	}{
+		{"/", "", "", language.English},
+		{"/", "", "de-DE,de;q=0.9,en;q=0.8", language.German},
+		{"/", "", "fr-FR,fr;q=0.9", language.English},
+		{"/", "", "fr-FR,de;q=0.5", language.German},
+		{"/", "de", "en-US", language.German},
+		{"/?lang=en", "de", "de-DE", language.English},
+		{"/?lang=xx", "", "de-DE", language.German},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.url, nil)
+		if tt.cookie != "" {
+			r.Header.Set("Cookie", languageCookie+"="+tt.cookie)
+		}
+		if tt.accept != "" {
+			r.Header.Set("Accept-Language", tt.accept)
+		}
+		if got := requestLanguage(r); got != tt.want {
+			t.Errorf("requestLanguage(%s, cookie %q, %q) = %v, want %v", tt.url, tt.cookie, tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	de, en := translatorFor(language.German), translatorFor(language.English)
+	day := time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct{ got, want string }{
+		{de.N("results.count", 1), "1 Vorstellung"},
+		{en.N("results.count", 3), "3 screenings"},
+		{de.T("price.reduced", "7,50 €"), "ermäßigt 7,50 €"},
+		{de.T("unknown.key"), "unknown.key"},
+		{de.Date(day), "08.03.2025"},
+		{en.Date(day), "Mar 8, 2025"},
+		{de.Weekday(day.Weekday()), "Samstag"},
+		{en.ShortWeekday(day.Weekday()), "Sat"},
+		{de.Money(950, "EUR"), "9,50 €"},
+		{en.Money(950, "EUR"), "9.50 €"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package delivery
+
+// Message catalogs of the UI. Keys group messages by where they are shown.
+// Names of periods, orders and tags are only listed where they differ from
+// the English labels of the domain.
+
+var messagesEN = map[string]string{
+	"title":       "Kino Berlin",
+	"description": "Programme of Berlin cinemas",
+	"error":       "An error occurred: %s",
+
+	"search.placeholder": "Search, e.g. metropolis or cinema:babylon after:19:00 -tag:kids",
+
+	"filters.any_day":       "any day",
+	"filters.all_dates":     "all",
+	"filters.to":            "to",
+	"filters.from":          "from",
+	"filters.until":         "until",
+	"filters.hide_sold_out": "Hide sold out",
+	"filters.max_price":     "Max. price",
+	"filters.max_runtime":   "Max. runtime",
+	"filters.minutes":       "min",
+	"filters.ends_before":   "Ends before",
+	"filters.show":          "Show",
+	"filters.sort":          "Sort",
+	"filters.apply":         "Apply",
+
+	"view.list":     "List",
+	"view.week":     "Week",
+	"rows.cinema":   "by cinema",
+	"rows.film":     "by film",
+	"order.default": "Default order",
+
+	"presets.name":           "Preset name",
+	"presets.save":           "Save preset",
+	"presets.share":          "Share",
+	"presets.apply":          "Apply preset",
+	"presets.delete":         "Delete preset",
+	"presets.copied":         "Link copied",
+	"presets.in_address_bar": "Link in address bar",
+
+	"results.count.one":   "%d screening",
+	"results.count.other": "%d screenings",
+	"results.more":        "More screenings",
+
+	"screening.with":            "With",
+	"screening.minutes":         "%d minutes",
+	"screening.runtime_unknown": "Runtime unknown",
+	"screening.at":              "at",
+	"screening.after_midnight":  "(after midnight)",
+	"screening.tickets":         "Tickets",
+
+	"availability.available": "Tickets available",
+	"availability.few_seats": "Few seats left",
+	"availability.sold_out":  "Sold out",
+
+	"price.free":       "Free",
+	"price.cinema_day": "(cinema day)",
+	"price.reduced":    "reduced %s",
+
+	"week.previous": "‹ Previous week",
+	"week.next":     "Next week ›",
+	"week.empty":    "No screenings this week.",
+}
+
+var messagesDE = map[string]string{
+	"title":       "Kino Berlin",
+	"description": "Das Programm der Berliner Kinos",
+	"error":       "Ein Fehler ist aufgetreten: %s",
+
+	"search.placeholder": "Suche, z. B. metropolis oder cinema:babylon after:19:00 -tag:kids",
+
+	"filters.any_day":       "jeder Tag",
+	"filters.all_dates":     "alle",
+	"filters.to":            "bis",
+	"filters.from":          "ab",
+	"filters.until":         "bis",
+	"filters.hide_sold_out": "Ausverkaufte ausblenden",
+	"filters.max_price":     "Max. Preis",
+	"filters.max_runtime":   "Max. Länge",
+	"filters.minutes":       "Min.",
+	"filters.ends_before":   "Endet vor",
+	"filters.show":          "Ansicht",
+	"filters.sort":          "Sortierung",
+	"filters.apply":         "Anwenden",
+
+	"view.list":     "Liste",
+	"view.week":     "Woche",
+	"rows.cinema":   "nach Kino",
+	"rows.film":     "nach Film",
+	"order.default": "Standard",
+
+	"order.start":   "Beginn",
+	"order.title":   "Titel",
+	"order.cinema":  "Kino",
+	"order.runtime": "Länge",
+	"order.added":   "Neu hinzugefügt",
+
+	"period.today":        "Heute",
+	"period.tomorrow":     "Morgen",
+	"period.this-weekend": "Dieses Wochenende",
+	"period.next-7-days":  "Nächste 7 Tage",
+
+	"tag.preview": "Vorpremiere",
+	"tag.guests":  "Mit Gästen",
+	"tag.shorts":  "Kurzfilme",
+	"tag.kids":    "Kinder",
+
+	"presets.name":           "Name der Vorlage",
+	"presets.save":           "Vorlage speichern",
+	"presets.share":          "Teilen",
+	"presets.apply":          "Vorlage anwenden",
+	"presets.delete":         "Vorlage löschen",
+	"presets.copied":         "Link kopiert",
+	"presets.in_address_bar": "Link in der Adresszeile",
+
+	"results.count.one":   "%d Vorstellung",
+	"results.count.other": "%d Vorstellungen",
+	"results.more":        "Weitere Vorstellungen",
+
+	"screening.with":            "Mit",
+	"screening.minutes":         "%d Minuten",
+	"screening.runtime_unknown": "Länge unbekannt",
+	"screening.at":              "um",
+	"screening.after_midnight":  "(nach Mitternacht)",
+	"screening.tickets":         "Tickets",
+
+	"availability.available": "Tickets verfügbar",
+	"availability.few_seats": "Nur noch wenige Plätze",
+	"availability.sold_out":  "Ausverkauft",
+
+	"price.free":       "Eintritt frei",
+	"price.cinema_day": "(Kinotag)",
+	"price.reduced":    "ermäßigt %s",
+
+	"week.previous": "‹ Vorherige Woche",
+	"week.next":     "Nächste Woche ›",
+	"week.empty":    "Diese Woche keine Vorstellungen.",
+}
@@ -3,10 +3,197 @@ package delivery
 import "time"
 
 type ScreeningViewModel struct {
-	Title         string
-	Cinema        string
-	Duration      int
-	Date          time.Time
+	Title    string
+	Cinema   string
+	Duration int
+	// Date is the start in Berlin and Day its cinema day, which is the day
+	// before for late shows after midnight.
+	Date time.Time
+	Day  time.Time
+	// End is the end time like "21:45", empty if the duration is unknown.
+	End      string
+	Language string
+	Director string
+	Cast     []string
+	// Facts are short attributes like genres, age rating and format.
+	Facts []string
+	Tags  []string
+	// Price is the formatted price on the day of the screening, empty if
+	// unknown.
+	Price         string
+	Year          int
+	Country       string
+	Notes         []string
 	Link          string
+	BookingLink   string
 	ThumbnailLink string
+	// Availability is the badge text and AvailabilityClass its CSS
+	// modifier. Both are empty if the availability is unknown.
+	Availability      string
+	AvailabilityClass string
+}
+
+// AfterMidnight reports whether the screening starts after midnight of its
+// cinema day.
+func (vm ScreeningViewModel) AfterMidnight() bool {
+	return vm.Date.Day() != vm.Day.Day()
+}
+
+// ScreeningJSON is the JSON representation of a screening.
+type ScreeningJSON struct {
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description,omitempty"`
+	Start        time.Time  `json:"start"`
+	Day          string     `json:"day"`
+	End          *time.Time `json:"end,omitempty"`
+	Duration     int        `json:"duration_minutes,omitempty"`
+	Cinema       string     `json:"cinema"`
+	Language     string     `json:"language,omitempty"`
+	Director     string     `json:"director,omitempty"`
+	Cast         []string   `json:"cast,omitempty"`
+	Genres       []string   `json:"genres,omitempty"`
+	AgeRating    string     `json:"age_rating,omitempty"`
+	Year         int        `json:"year,omitempty"`
+	Country      string     `json:"country,omitempty"`
+	Notes        []string   `json:"notes,omitempty"`
+	Format       string     `json:"format,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Availability string     `json:"availability"`
+	Prices       *PriceJSON `json:"prices,omitempty"`
+	Links        LinksJSON  `json:"links"`
+}
+
+// PriceJSON holds prices as decimal strings like "9.50" to avoid rounding.
+type PriceJSON struct {
+	Currency    string   `json:"currency"`
+	Regular     string   `json:"regular"`
+	Reduced     string   `json:"reduced,omitempty"`
+	Special     string   `json:"special,omitempty"`
+	SpecialDays []string `json:"special_days,omitempty"`
+	// OnDay is the regular price on the day of the screening.
+	OnDay string `json:"on_day"`
+}
+
+type LinksJSON struct {
+	Details   string `json:"details,omitempty"`
+	Booking   string `json:"booking,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+type ProviderStatusViewModel struct {
+	Name                string     `json:"name"`
+	LastAttempt         *time.Time `json:"last_attempt,omitempty"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	Screenings          int        `json:"screenings"`
+	Breaker             string     `json:"breaker"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	BreakerRetryAt      *time.Time `json:"breaker_retry_at,omitempty"`
+}
+
+// PageViewModel is the whole page.
+type PageViewModel struct {
+	Filters FiltersViewModel
+	Results ResultsViewModel
+	// Languages link to the page in every language, Value is the URL.
+	Languages []OptionViewModel
+	// Error replaces the results if the search failed.
+	Error string
+}
+
+// FiltersViewModel is the filter form with the values of the current search.
+type FiltersViewModel struct {
+	Query       string
+	Periods     []OptionViewModel
+	Dates       []OptionViewModel
+	DatesTo     []OptionViewModel
+	After       string
+	Before      string
+	Groups      []OptionViewModel
+	Cinemas     []OptionViewModel
+	Weekdays    []OptionViewModel
+	Tags        []OptionViewModel
+	HideSoldOut bool
+	MaxPrice    string
+	MaxRuntime  string
+	EndsBefore  string
+	Views       []OptionViewModel
+	Rows        []OptionViewModel
+	Orders      []OptionViewModel
+	// Week is the Monday of the week shown in the week view.
+	Week string
+}
+
+// OptionViewModel is an option of a select or a checkbox.
+type OptionViewModel struct {
+	Value    string
+	Label    string
+	Selected bool
+}
+
+// ResultsViewModel is the result of a search, shown as a list or, if Week is
+// set, as a week grid.
+type ResultsViewModel struct {
+	Screenings []ScreeningViewModel
+	Week       *WeekViewModel
+	// Total is the number of matching screenings.
+	Total int
+	// Offset is the number of screenings on earlier pages.
+	Offset int
+	// More links to the next page, nil on the last page.
+	More *MoreViewModel
+	// Fragment is set if the results replace those of the page, which then
+	// also updates the filter form out of band.
+	Fragment bool
+}
+
+// MoreViewModel links to the next page of screenings, as whole page and as
+// fragment to append for infinite scrolling.
+type MoreViewModel struct {
+	URL      string
+	Fragment string
+}
+
+// WeekViewModel is a week of screenings as a grid with a row per cinema or
+// film, and as a list per day for small screens.
+type WeekViewModel struct {
+	Label string
+	// Week, Prev and Next are the Mondays of the week and the weeks before
+	// and after.
+	Week, Prev, Next string
+	// Rows is "cinema" or "film".
+	Rows string
+	Days []WeekDayViewModel
+	Grid []WeekRowViewModel
+	// Hours is the length of the timeline of every cell.
+	Hours int
+}
+
+type WeekDayViewModel struct {
+	Date   time.Time
+	Blocks []WeekBlockViewModel
+}
+
+type WeekRowViewModel struct {
+	Name  string
+	Cells []WeekCellViewModel
+}
+
+type WeekCellViewModel struct {
+	Blocks []WeekBlockViewModel
+}
+
+// WeekBlockViewModel is a screening in the week grid. Its position and size
+// in the cell are given in percent.
+type WeekBlockViewModel struct {
+	Title  string
+	Cinema string
+	Start  string
+	End    string
+	// Estimated is set if the runtime is unknown and End assumed.
+	Estimated bool
+	Link      string
+
+	Top, Height, Left, Width float64
 }
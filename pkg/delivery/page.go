@@ -0,0 +1,158 @@
+package delivery
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+// handleIndex renders the whole page with the filters and, if the URL has a
+// query, the matching screenings, so that searches can be bookmarked and
+// shared. Requests by htmx only get the screenings.
+func (h *Handler) handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "HX-Request")
+	if isFragmentRequest(r) {
+		h.handleScreenings(w, r)
+		return
+	}
+
+	tr := requestTranslator(w, r)
+	filters, err := h.filtersViewModel(r, tr)
+	if err != nil {
+		h.renderError(w, tr, err)
+		return
+	}
+
+	page := PageViewModel{
+		Filters:   filters,
+		Languages: languageOptions(r, tr),
+	}
+	// the language toggle alone is no search
+	query := r.URL.Query()
+	query.Del("lang")
+	if len(query) > 0 {
+		results, err := h.resultsViewModel(r, tr)
+		var parseErr *domain.ParseError
+		switch {
+		case errors.As(err, &parseErr):
+			page.Error = err.Error()
+		case err != nil:
+			log.Printf("Error: %v", err)
+			page.Error = err.Error()
+		default:
+			page.Results = results
+		}
+	}
+
+	if err := h.renderPage(w, tr, "index", page); err != nil {
+		h.renderError(w, tr, err)
+		return
+	}
+}
+
+// isFragmentRequest reports whether r was made by htmx and only wants the
+// part of the page it swaps. htmx restoring a page missing from its history
+// cache wants the whole page.
+func isFragmentRequest(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true" && r.Header.Get("HX-History-Restore-Request") != "true"
+}
+
+// languageOptions returns links to the page of r in every language.
+func languageOptions(r *http.Request, tr *translator) []OptionViewModel {
+	options := make([]OptionViewModel, len(languages))
+	for i, lang := range languages {
+		query := r.URL.Query()
+		query.Set("lang", lang.String())
+		options[i] = OptionViewModel{
+			Value:    "/?" + query.Encode(),
+			Label:    languageNames[lang],
+			Selected: lang == tr.lang,
+		}
+	}
+	return options
+}
+
+// filtersViewModel returns the filter form with the values of r selected.
+func (h *Handler) filtersViewModel(r *http.Request, tr *translator) (FiltersViewModel, error) {
+	if err := r.ParseForm(); err != nil {
+		return FiltersViewModel{}, err
+	}
+
+	cinemas, err := h.app.GetAvailableCinemas()
+	if err != nil {
+		return FiltersViewModel{}, err
+	}
+
+	dates, err := h.app.GetAvailableDates()
+	if err != nil {
+		return FiltersViewModel{}, err
+	}
+
+	tags, err := h.app.GetAvailableTags()
+	if err != nil {
+		return FiltersViewModel{}, err
+	}
+
+	vm := FiltersViewModel{
+		Query:       r.FormValue("q"),
+		After:       r.FormValue("after"),
+		Before:      r.FormValue("before"),
+		HideSoldOut: r.FormValue("hide_sold_out") != "",
+		MaxPrice:    r.FormValue("max_price"),
+		MaxRuntime:  r.FormValue("max_runtime"),
+		EndsBefore:  r.FormValue("ends_before"),
+		Week:        r.FormValue("week"),
+		Views: []OptionViewModel{
+			option(r, "view", "list", tr.T("view.list")),
+			option(r, "view", "week", tr.T("view.week")),
+		},
+		Orders: []OptionViewModel{
+			option(r, "sort", "", tr.T("order.default")),
+		},
+		Rows: []OptionViewModel{
+			option(r, "rows", rowsByCinema, tr.T("rows.cinema")),
+			option(r, "rows", rowsByFilm, tr.T("rows.film")),
+		},
+	}
+
+	for _, o := range domain.AllOrders {
+		vm.Orders = append(vm.Orders, option(r, "sort", string(o), tr.label("order."+string(o), o.Label())))
+	}
+	for _, p := range domain.AllPeriods {
+		vm.Periods = append(vm.Periods, option(r, "period", string(p), tr.label("period."+string(p), p.Label())))
+	}
+	for _, d := range dates {
+		value := d.Format(time.DateOnly)
+		label := tr.ShortWeekday(d.Weekday()) + " " + tr.Date(d)
+		vm.Dates = append(vm.Dates, option(r, "dates", value, label))
+		vm.DatesTo = append(vm.DatesTo, option(r, "date_to", value, label))
+	}
+	for _, g := range h.app.CinemaGroups().Names() {
+		vm.Groups = append(vm.Groups, option(r, "groups", g, g))
+	}
+	for _, c := range cinemas {
+		vm.Cinemas = append(vm.Cinemas, option(r, "cinemas", c, c))
+	}
+	for _, d := range weekdays {
+		vm.Weekdays = append(vm.Weekdays, option(r, "weekdays", d.String(), tr.ShortWeekday(d)))
+	}
+	for _, t := range tags {
+		vm.Tags = append(vm.Tags, option(r, "tags", string(t), tr.label("tag."+string(t), t.Label())))
+	}
+
+	return vm, nil
+}
+
+// option returns an option of the form field name that is selected if r has
+// value for name.
+func option(r *http.Request, name, value, label string) OptionViewModel {
+	return OptionViewModel{
+		Value:    value,
+		Label:    label,
+		Selected: slices.Contains(r.Form[name], value),
+	}
+}
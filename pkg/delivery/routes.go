@@ -1,34 +1,74 @@
 package delivery
 
 import (
-	"html/template"
+	"io"
+	"io/fs"
 	"net/http"
-	"path/filepath"
+
+	"golang.org/x/text/language"
 
 	"github.com/PhilippReinke/kino-berlin/pkg/app"
 )
 
 type Handler struct {
-	app       *app.App
-	templates *template.Template
-	staticDir string
+	app         *app.App
+	templates   map[language.Tag]*templates
+	templateFS  fs.FS
+	reload      bool
+	staticFiles fs.FS
 }
 
-func NewHandler(a *app.App, templateDir, staticDir string) (*Handler, error) {
-	tmpl, err := template.ParseGlob(filepath.Join(templateDir, "*.html"))
-	if err != nil {
-		return nil, err
+// NewHandler returns a handler rendering the templates in templateFS and
+// serving the files in staticFS. If reload is set, the templates are parsed
+// again for every request, so that changes show without a restart.
+func NewHandler(a *app.App, templateFS, staticFS fs.FS, reload bool) (*Handler, error) {
+	tmpl := make(map[language.Tag]*templates, len(languages))
+	for _, lang := range languages {
+		t, err := parseTemplates(templateFS, translatorFor(lang).funcs())
+		if err != nil {
+			return nil, err
+		}
+		tmpl[lang] = t
 	}
 
 	return &Handler{
-		app:       a,
-		templates: tmpl,
-		staticDir: staticDir,
+		app:         a,
+		templates:   tmpl,
+		templateFS:  templateFS,
+		reload:      reload,
+		staticFiles: staticFS,
 	}, nil
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
-	mux.Handle("GET /", http.FileServer(http.Dir(h.staticDir)))
+	mux.HandleFunc("GET /{$}", h.handleIndex)
+	mux.Handle("GET /", http.FileServerFS(h.staticFiles))
 	mux.HandleFunc("GET /api/selects", h.handleSelects)
+	mux.HandleFunc("GET /api/screenings", h.handleScreenings)
 	mux.HandleFunc("POST /api/screenings", h.handleScreenings)
+	mux.HandleFunc("GET /api/screenings.json", h.handleScreeningsJSON)
+	mux.HandleFunc("GET /api/status", h.handleStatus)
+}
+
+func (h *Handler) loadTemplates(tr *translator) (*templates, error) {
+	if !h.reload {
+		return h.templates[tr.lang], nil
+	}
+	return parseTemplates(h.templateFS, tr.funcs())
+}
+
+func (h *Handler) renderFragment(w io.Writer, tr *translator, name string, data any) error {
+	tmpl, err := h.loadTemplates(tr)
+	if err != nil {
+		return err
+	}
+	return tmpl.fragment(w, name, data)
+}
+
+func (h *Handler) renderPage(w io.Writer, tr *translator, name string, data any) error {
+	tmpl, err := h.loadTemplates(tr)
+	if err != nil {
+		return err
+	}
+	return tmpl.page(w, name, data)
 }
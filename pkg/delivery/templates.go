@@ -0,0 +1,56 @@
+package delivery
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// templates are the fragments swapped in by htmx and the pages. A page in
+// pages/ defines the "content" and optionally the "title" of the layout and
+// can use all fragments.
+type templates struct {
+	fragments *template.Template
+	pages     map[string]*template.Template
+}
+
+func parseTemplates(fsys fs.FS, funcs template.FuncMap) (*templates, error) {
+	fragments, err := template.New("").Funcs(funcs).ParseFS(fsys, "*.html")
+	if err != nil {
+		return nil, fmt.Errorf("parsing templates: %w", err)
+	}
+
+	files, err := fs.Glob(fsys, "pages/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("listing pages: %w", err)
+	}
+
+	pages := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		page, err := fragments.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("cloning templates: %w", err)
+		}
+		if page, err = page.ParseFS(fsys, file); err != nil {
+			return nil, fmt.Errorf("parsing page %s: %w", file, err)
+		}
+		pages[strings.TrimSuffix(path.Base(file), ".html")] = page
+	}
+
+	return &templates{fragments: fragments, pages: pages}, nil
+}
+
+func (t *templates) fragment(w io.Writer, name string, data any) error {
+	return t.fragments.ExecuteTemplate(w, name, data)
+}
+
+func (t *templates) page(w io.Writer, name string, data any) error {
+	page, ok := t.pages[name]
+	if !ok {
+		return fmt.Errorf("no page %q", name)
+	}
+	return page.ExecuteTemplate(w, "layout", data)
+}
@@ -0,0 +1,163 @@
+package delivery
+
+import (
+	"cmp"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+// Rows of the week grid.
+const (
+	rowsByCinema = "cinema"
+	rowsByFilm   = "film"
+)
+
+// weekStart returns the Monday of the week asked for by the week parameter of
+// r, a date like "2025-03-26", and of the current cinema day otherwise.
+func weekStart(r *http.Request, days domain.CinemaDays) time.Time {
+	if date, err := time.Parse(time.DateOnly, r.FormValue("week")); err == nil {
+		return domain.WeekStart(date)
+	}
+	return domain.WeekStart(days.Day(time.Now()))
+}
+
+// weekRows returns how r wants the rows of the week grid.
+func weekRows(r *http.Request) string {
+	if r.FormValue("rows") == rowsByFilm {
+		return rowsByFilm
+	}
+	return rowsByCinema
+}
+
+// newWeekViewModel lays out the screenings of the week starting on start as a
+// grid with a row per cinema or film. Blocks are placed on a timeline from
+// the earliest start to the latest end of the week, so that rows line up.
+func newWeekViewModel(start time.Time, rows string, screenings []domain.Screening, cinemaDays domain.CinemaDays, tr *translator) *WeekViewModel {
+	end := start.AddDate(0, 0, 6)
+	vm := &WeekViewModel{
+		Label: tr.DayMonth(start) + "–" + tr.Date(end),
+		Week:  start.Format(time.DateOnly),
+		Prev:  start.AddDate(0, 0, -7).Format(time.DateOnly),
+		Next:  start.AddDate(0, 0, 7).Format(time.DateOnly),
+		Rows:  rows,
+	}
+
+	days := make([]time.Time, 7)
+	for i := range days {
+		days[i] = start.AddDate(0, 0, i)
+		vm.Days = append(vm.Days, WeekDayViewModel{Date: days[i]})
+	}
+
+	type block struct {
+		row        string
+		day        int
+		start, end int // minutes since midnight of the cinema day
+		vm         WeekBlockViewModel
+	}
+
+	var blocks []block
+	first, last := math.MaxInt, 0
+	for _, s := range screenings {
+		cinemaDay := cinemaDays.Day(s.Start)
+		day := slices.IndexFunc(days, cinemaDay.Equal)
+		if day < 0 {
+			continue
+		}
+
+		duration := s.Duration
+		if duration <= 0 {
+			duration = domain.AssumedDuration
+		}
+
+		b := block{
+			row:   s.Cinema,
+			day:   day,
+			start: minutesIntoDay(s.Start, cinemaDay),
+			vm: WeekBlockViewModel{
+				Title:     s.Title,
+				Cinema:    s.Cinema,
+				Start:     s.Start.In(domain.Berlin).Format("15:04"),
+				End:       s.Start.Add(duration).In(domain.Berlin).Format("15:04"),
+				Estimated: s.Duration <= 0,
+				Link:      cmp.Or(s.Links.Booking, s.Links.Details),
+			},
+		}
+		if rows == rowsByFilm {
+			b.row = s.Title
+		}
+		b.end = b.start + int(duration.Minutes())
+
+		first, last = min(first, b.start), max(last, b.end)
+		blocks = append(blocks, b)
+	}
+	if len(blocks) == 0 {
+		return vm
+	}
+
+	// whole hours around all screenings
+	first = first / 60 * 60
+	last = (last + 59) / 60 * 60
+	vm.Hours = (last - first) / 60
+	span := float64(last - first)
+
+	slices.SortFunc(blocks, func(a, b block) int {
+		return cmp.Or(cmp.Compare(a.row, b.row), cmp.Compare(a.day, b.day), cmp.Compare(a.start, b.start))
+	})
+
+	for i := 0; i < len(blocks); {
+		row := WeekRowViewModel{Name: blocks[i].row, Cells: make([]WeekCellViewModel, 7)}
+		// screenings overlapping in a cell are put side by side in lanes
+		laneEnds := make([][]int, 7)
+		lanes := make([][]int, 7)
+		for ; i < len(blocks) && blocks[i].row == row.Name; i++ {
+			b := blocks[i]
+			b.vm.Top = percent(float64(b.start-first) / span)
+			b.vm.Height = percent(float64(b.end-b.start) / span)
+
+			lane := slices.IndexFunc(laneEnds[b.day], func(end int) bool { return end <= b.start })
+			if lane < 0 {
+				lane = len(laneEnds[b.day])
+				laneEnds[b.day] = append(laneEnds[b.day], 0)
+			}
+			laneEnds[b.day][lane] = b.end
+			lanes[b.day] = append(lanes[b.day], lane)
+
+			row.Cells[b.day].Blocks = append(row.Cells[b.day].Blocks, b.vm)
+			vm.Days[b.day].Blocks = append(vm.Days[b.day].Blocks, b.vm)
+		}
+		for day, cell := range row.Cells {
+			for j := range cell.Blocks {
+				cell.Blocks[j].Width = percent(1 / float64(len(laneEnds[day])))
+				cell.Blocks[j].Left = percent(float64(lanes[day][j]) / float64(len(laneEnds[day])))
+			}
+		}
+		vm.Grid = append(vm.Grid, row)
+	}
+
+	for i := range vm.Days {
+		slices.SortStableFunc(vm.Days[i].Blocks, func(a, b WeekBlockViewModel) int {
+			return cmp.Compare(a.Top, b.Top)
+		})
+	}
+
+	return vm
+}
+
+// minutesIntoDay returns the minutes from midnight of day to t, counting on
+// past 24:00 for screenings after midnight.
+func minutesIntoDay(t, day time.Time) int {
+	t = t.In(domain.Berlin)
+	minutes := t.Hour()*60 + t.Minute()
+	if domain.Date(t).After(day) {
+		minutes += 24 * 60
+	}
+	return minutes
+}
+
+func percent(f float64) float64 {
+	return math.Round(f*10000) / 100
+}
@@ -0,0 +1,85 @@
+package delivery
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+func TestNewWeekViewModel(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, domain.Berlin)
+	}
+	screenings := []domain.Screening{
+		{Title: "Alpha", Cinema: "Kino A", Start: at(3, 20, 0), Duration: 2 * time.Hour},
+		// overlaps Alpha
+		{Title: "Beta", Cinema: "Kino A", Start: at(3, 21, 0), Duration: 90 * time.Minute},
+		// after Alpha, before Beta ends
+		{Title: "Gamma", Cinema: "Kino A", Start: at(3, 23, 0), Duration: 90 * time.Minute},
+		// Sunday 00:30, the late show of Saturday
+		{Title: "Alpha", Cinema: "Kino B", Start: at(9, 0, 30), Duration: 100 * time.Minute},
+		// next week
+		{Title: "Alpha", Cinema: "Kino B", Start: at(10, 20, 0), Duration: 2 * time.Hour},
+	}
+	days := domain.CinemaDays{Rollover: 4 * time.Hour}
+	tr := translatorFor(language.English)
+
+	vm := newWeekViewModel(at(3, 0, 0), rowsByCinema, screenings, days, tr)
+
+	if vm.Week != "2025-03-03" || vm.Prev != "2025-02-24" || vm.Next != "2025-03-10" {
+		t.Errorf("weeks = %s, %s, %s, want 2025-03-03, 2025-02-24, 2025-03-10", vm.Week, vm.Prev, vm.Next)
+	}
+	// from 20:00 to 02:10 of the next day, rounded to whole hours
+	if vm.Hours != 7 {
+		t.Errorf("Hours = %d, want 7", vm.Hours)
+	}
+
+	var rows []string
+	for _, row := range vm.Grid {
+		rows = append(rows, row.Name)
+	}
+	if want := []string{"Kino A", "Kino B"}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows = %q, want %q", rows, want)
+	}
+
+	type placement struct {
+		title       string
+		left, width float64
+	}
+	var monday []placement
+	for _, b := range vm.Grid[0].Cells[0].Blocks {
+		monday = append(monday, placement{b.Title, b.Left, b.Width})
+	}
+	// Gamma takes the lane of Alpha, which has ended
+	want := []placement{{"Alpha", 0, 50}, {"Beta", 50, 50}, {"Gamma", 0, 50}}
+	if !reflect.DeepEqual(monday, want) {
+		t.Errorf("Monday of Kino A = %+v, want %+v", monday, want)
+	}
+	if b := vm.Grid[0].Cells[0].Blocks[1]; b.Top != 14.29 || b.Height != 21.43 {
+		t.Errorf("Beta at %v%% for %v%%, want 14.29%% for 21.43%%", b.Top, b.Height)
+	}
+
+	saturday := vm.Grid[1].Cells[5].Blocks
+	if len(saturday) != 1 || saturday[0].Start != "00:30" || saturday[0].Top != 64.29 {
+		t.Errorf("Saturday of Kino B = %+v, want the late show at 00:30 after midnight", saturday)
+	}
+	if len(vm.Days[6].Blocks) != 0 {
+		t.Errorf("Sunday has %d screenings, want none", len(vm.Days[6].Blocks))
+	}
+
+	vm = newWeekViewModel(at(3, 0, 0), rowsByFilm, screenings, days, tr)
+	rows = nil
+	for _, row := range vm.Grid {
+		rows = append(rows, row.Name)
+	}
+	if want := []string{"Alpha", "Beta", "Gamma"}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows by film = %q, want %q", rows, want)
+	}
+	if cell := vm.Grid[0].Cells; len(cell[0].Blocks) != 1 || len(cell[5].Blocks) != 1 {
+		t.Errorf("Alpha on Monday and Saturday = %d and %d screenings, want 1 and 1", len(cell[0].Blocks), len(cell[5].Blocks))
+	}
+}
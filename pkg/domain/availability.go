@@ -0,0 +1,27 @@
+package domain
+
+// Availability tells whether tickets for a screening can still be bought.
+type Availability int
+
+const (
+	// AvailabilityUnknown is used if the source does not say.
+	AvailabilityUnknown Availability = iota
+	AvailabilityAvailable
+	// AvailabilityFewSeats means only a few tickets are left.
+	AvailabilityFewSeats
+	AvailabilitySoldOut
+)
+
+// String returns a slug like "sold-out", as used by the API.
+func (a Availability) String() string {
+	switch a {
+	case AvailabilityAvailable:
+		return "available"
+	case AvailabilityFewSeats:
+		return "few-seats"
+	case AvailabilitySoldOut:
+		return "sold-out"
+	default:
+		return "unknown"
+	}
+}
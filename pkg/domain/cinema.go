@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// CinemaGroups maps names of groups like "Yorck venues" or
+// "Kreuzberg/Neukölln" to the names of their cinemas.
+type CinemaGroups map[string][]string
+
+// Names returns the group names in alphabetical order.
+func (g CinemaGroups) Names() []string {
+	return slices.Sorted(maps.Keys(g))
+}
+
+// Lookup returns the cinemas of the group with the given name, ignoring
+// case and diacritics.
+func (g CinemaGroups) Lookup(name string) (cinemas []string, ok bool) {
+	if cinemas, ok := g[name]; ok {
+		return cinemas, true
+	}
+	folded := Normalize(strings.TrimSpace(name))
+	for group, cinemas := range g {
+		if Normalize(group) == folded {
+			return cinemas, true
+		}
+	}
+	return nil, false
+}
+
+// Expand returns the cinemas of the named groups, without duplicates.
+// Unknown group names are ignored.
+func (g CinemaGroups) Expand(groups ...string) []string {
+	var cinemas []string
+	for _, name := range groups {
+		members, _ := g.Lookup(name)
+		for _, c := range members {
+			if !slices.Contains(cinemas, c) {
+				cinemas = append(cinemas, c)
+			}
+		}
+	}
+	return cinemas
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCinemaGroups_Expand(t *testing.T) {
+	groups := CinemaGroups{
+		"Yorck venues":       {"Delphi LUX", "Babylon Kreuzberg", "Rollberg"},
+		"Kreuzberg/Neukölln": {"Babylon Kreuzberg", "Rollberg", "Moviemento"},
+	}
+
+	tests := []struct {
+		groups []string
+		want   []string
+	}{
+		{[]string{"Yorck venues"}, []string{"Delphi LUX", "Babylon Kreuzberg", "Rollberg"}},
+		{[]string{"kreuzberg/neukolln"}, []string{"Babylon Kreuzberg", "Rollberg", "Moviemento"}},
+		{[]string{"Yorck venues", "Kreuzberg/Neukölln"}, []string{"Delphi LUX", "Babylon Kreuzberg", "Rollberg", "Moviemento"}},
+		{[]string{"Mitte"}, nil},
+	}
+
+	for _, tt := range tests {
+		if got := groups.Expand(tt.groups...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Expand(%q) = %q, want %q", tt.groups, got, tt.want)
+		}
+	}
+
+	if got, want := groups.Names(), []string{"Kreuzberg/Neukölln", "Yorck venues"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %q, want %q", got, want)
+	}
+}
+
+func TestQueryParser_CinemaGroups(t *testing.T) {
+	p := QueryParser{CinemaGroups: CinemaGroups{
+		"Neukölln": {"Yorck Kino", "Delphi LUX"},
+	}}
+
+	q, err := p.Parse(`cinema:neukolln OR cinema:"kino babylon" lang:df`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []ScreeningID
+	for _, s := range queryFixtures(t) {
+		if q.Filter(s) {
+			got = append(got, s.ID)
+		}
+	}
+	if want := []ScreeningID{"delphi", "babylon-early", "yorck"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("matched %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is returned by providers if a source responds with an unexpected
+// status code.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s responded with %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Filter returns true if filter matches.
 //
@@ -8,11 +11,12 @@ import "time"
 // date.
 type Filter func(Screening) bool
 
-func DateFilter(date time.Time) Filter {
-	year, month, day := date.Date()
+// DateFilter matches screenings on the cinema day with the calendar date of
+// date.
+func (c CinemaDays) DateFilter(date time.Time) Filter {
+	d := Date(date)
 	return func(s Screening) bool {
-		sYear, sMonth, sDay := s.Start.Date()
-		return sYear == year && sMonth == month && sDay == day
+		return c.Day(s.Start).Equal(d)
 	}
 }
 
@@ -28,8 +32,140 @@ func ExpiredScreeningFilter() Filter {
 	}
 }
 
-func CinemaFilter(cinema string) Filter {
+// CinemaFilter matches screenings in any of cinemas.
+func CinemaFilter(cinemas ...string) Filter {
+	return func(s Screening) bool {
+		return slices.Contains(cinemas, s.Cinema)
+	}
+}
+
+// AvailableFilter matches screenings that are not sold out. Screenings with
+// unknown availability match.
+func AvailableFilter() Filter {
+	return func(s Screening) bool {
+		return s.Availability != AvailabilitySoldOut
+	}
+}
+
+// TagFilter matches screenings that have all given tags.
+func TagFilter(tags ...Tag) Filter {
+	return func(s Screening) bool {
+		for _, t := range tags {
+			if !s.Tags.Has(t) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MaxPriceFilter matches screenings whose regular price on the day of the
+// screening is at most max. Screenings with unknown prices match.
+func MaxPriceFilter(max Money) Filter {
+	return func(s Screening) bool {
+		return !s.Prices.Known() || s.Prices.On(s.Start) <= max
+	}
+}
+
+// StartsAfterFilter matches screenings starting at or after the time of day
+// t in Berlin, given as offset since midnight. Times are compared within the
+// cinema day, so with a Rollover of 04:00 a screening at 00:30 starts after
+// 22:00.
+func (c CinemaDays) StartsAfterFilter(t time.Duration) Filter {
+	return func(s Screening) bool {
+		return c.sinceDayStart(clock(s.Start)) >= c.sinceDayStart(t)
+	}
+}
+
+// StartsBeforeFilter matches screenings starting before the time of day t in
+// Berlin, given as offset since midnight. Times are compared within the
+// cinema day like in StartsAfterFilter.
+func (c CinemaDays) StartsBeforeFilter(t time.Duration) Filter {
+	return func(s Screening) bool {
+		return c.sinceDayStart(clock(s.Start)) < c.sinceDayStart(t)
+	}
+}
+
+// And matches screenings matching all filters. It matches everything if no
+// filter is given.
+func And(filters ...Filter) Filter {
+	return func(s Screening) bool {
+		for _, f := range filters {
+			if !f(s) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches screenings matching any of filters. It matches nothing if no
+// filter is given.
+func Or(filters ...Filter) Filter {
+	return func(s Screening) bool {
+		for _, f := range filters {
+			if f(s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches screenings not matching f.
+func Not(f Filter) Filter {
+	return func(s Screening) bool {
+		return !f(s)
+	}
+}
+
+// DateRangeFilter matches screenings on the cinema days with the calendar
+// dates of from to to, both included.
+func (c CinemaDays) DateRangeFilter(from, to time.Time) Filter {
+	first, last := Date(from), Date(to)
+	return func(s Screening) bool {
+		d := c.Day(s.Start)
+		return !d.Before(first) && !d.After(last)
+	}
+}
+
+// StartsBetweenFilter matches screenings starting from the time of day from
+// until before to in Berlin, given as offsets since midnight. If to is before
+// from within the cinema day, the window spans the start of the day, e.g.
+// 22:00 to 02:00 without a Rollover.
+func (c CinemaDays) StartsBetweenFilter(from, to time.Duration) Filter {
+	if c.sinceDayStart(to) < c.sinceDayStart(from) {
+		return Or(c.StartsAfterFilter(from), c.StartsBeforeFilter(to))
+	}
+	return And(c.StartsAfterFilter(from), c.StartsBeforeFilter(to))
+}
+
+// WeekdayFilter matches screenings whose cinema day is any of days.
+func (c CinemaDays) WeekdayFilter(days ...time.Weekday) Filter {
+	return func(s Screening) bool {
+		return slices.Contains(days, c.Day(s.Start).Weekday())
+	}
+}
+
+// DurationFilter matches screenings lasting at least min and, unless max is
+// zero, at most max. Screenings with unknown duration match, as many
+// providers do not publish it.
+func DurationFilter(min, max time.Duration) Filter {
+	return func(s Screening) bool {
+		if s.Duration <= 0 {
+			return true
+		}
+		return s.Duration >= min && (max == 0 || s.Duration <= max)
+	}
+}
+
+// EndsBeforeFilter matches screenings ending at or before the time of day t
+// in Berlin, given as offset since midnight. The end is compared within the
+// cinema day of the start, so a screening running past midnight does not end
+// before 22:30. Unknown durations are assumed to be AssumedDuration.
+func (c CinemaDays) EndsBeforeFilter(t time.Duration) Filter {
 	return func(s Screening) bool {
-		return s.Cinema == cinema
+		end, _ := s.End()
+		return c.sinceDayStart(clock(s.Start))+end.Sub(s.Start) <= c.sinceDayStart(t)
 	}
 }
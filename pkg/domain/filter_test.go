@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationFilters(t *testing.T) {
+	at := func(hour, minute int, duration time.Duration) Screening {
+		return Screening{Start: time.Date(2025, 3, 7, hour, minute, 0, 0, Berlin), Duration: duration}
+	}
+	c := func(s string) time.Duration {
+		d, err := ParseClock(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	midnight, late := CinemaDays{}, CinemaDays{Rollover: 4 * time.Hour}
+
+	tests := []struct {
+		name      string
+		filter    Filter
+		screening Screening
+		want      bool
+	}{
+		{"short enough", DurationFilter(0, 100*time.Minute), at(20, 0, 95*time.Minute), true},
+		{"exactly max", DurationFilter(0, 100*time.Minute), at(20, 0, 100*time.Minute), true},
+		{"too long", DurationFilter(0, 100*time.Minute), at(20, 0, 165*time.Minute), false},
+		{"too short", DurationFilter(60*time.Minute, 0), at(20, 0, 15*time.Minute), false},
+		{"unknown runtime", DurationFilter(60*time.Minute, 100*time.Minute), at(20, 0, 0), true},
+		{"ends in time", midnight.EndsBeforeFilter(c("22:30")), at(20, 0, 150*time.Minute), true},
+		{"ends too late", midnight.EndsBeforeFilter(c("22:30")), at(20, 45, 106*time.Minute), false},
+		{"unknown runtime ends in time", midnight.EndsBeforeFilter(c("22:30")), at(20, 0, 0), true},
+		{"unknown runtime ends too late", midnight.EndsBeforeFilter(c("22:30")), at(21, 0, 0), false},
+		{"runs past midnight", midnight.EndsBeforeFilter(c("22:30")), at(23, 0, 90*time.Minute), false},
+		{"ends after midnight", late.EndsBeforeFilter(c("01:00")), at(22, 30, 2*time.Hour), true},
+		{"late show ends too late", late.EndsBeforeFilter(c("01:00")), at(23, 30, 2*time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter(tt.screening); got != tt.want {
+			t.Errorf("%s: filter(%v, %v) = %v, want %v", tt.name, tt.screening.Start, tt.screening.Duration, got, tt.want)
+		}
+	}
+}
+
+func TestParseRuntime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"100", 100 * time.Minute, true},
+		{"1h40m", 100 * time.Minute, true},
+		{"0", 0, false},
+		{"-5", 0, false},
+		{"long", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRuntime(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseRuntime(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
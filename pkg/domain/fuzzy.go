@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"strings"
+)
+
+// TitleMatchThreshold is the similarity from which a title is considered to
+// match a query.
+const TitleMatchThreshold = 0.75
+
+// EditDistance returns the number of insertions, deletions, substitutions
+// and transpositions of adjacent runes needed to turn a into b (the optimal
+// string alignment distance). Swapped letters are a common typo, so they
+// count as one edit.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// the last three rows of the distance matrix
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	row := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			row[j] = min(prev[j]+1, row[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				row[j] = min(row[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, row = prev, row, prev2
+	}
+
+	return prev[len(rb)]
+}
+
+// editSimilarity is the edit distance of a and b scaled to [0, 1], where 1
+// means equal.
+func editSimilarity(a, b string) float64 {
+	n := max(len([]rune(a)), len([]rune(b)))
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(EditDistance(a, b))/float64(n)
+}
+
+// trigramSimilarity is the Jaccard similarity of the trigrams of a and b.
+// Words are padded so that short words still have trigrams.
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(s) {
+		r := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			set[string(r[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// TitleSimilarity rates how well title matches query between 0 and 1. It
+// tolerates typos, punctuation, case and diacritics, and a query naming only
+// part of the title, e.g. "Metropolis" for "Metropolis (restaurierte
+// Fassung)", matches nearly as well as the whole title.
+func TitleSimilarity(query, title string) float64 {
+	q, t := Tokenize(query), Tokenize(title)
+	if len(q) == 0 || len(t) == 0 {
+		return 0
+	}
+	return tokenSimilarity(q, t)
+}
+
+func tokenSimilarity(q, t []string) float64 {
+	qs, ts := strings.Join(q, " "), strings.Join(t, " ")
+	if qs == ts {
+		return 1
+	}
+
+	whole := max(editSimilarity(qs, ts), trigramSimilarity(qs, ts))
+
+	// average of the best match of every query word, slightly below a match
+	// of the whole title so that those rank first
+	var words float64
+	for _, qw := range q {
+		var best float64
+		for _, tw := range t {
+			best = max(best, wordSimilarity(qw, tw))
+		}
+		words += best
+	}
+	words = 0.95 * words / float64(len(q))
+
+	return max(whole, words)
+}
+
+// wordSimilarity compares single words. Short words only match exactly,
+// as one typo changes them too much.
+func wordSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if len([]rune(a)) < 4 || len([]rune(b)) < 4 {
+		return 0
+	}
+	return editSimilarity(a, b)
+}
+
+// TitleAliases knows alternative titles of films, like the original and the
+// German release title. It is safe for concurrent use once created. A nil
+// *TitleAliases has no aliases.
+type TitleAliases struct {
+	// groups holds the normalised titles that name the same film
+	groups [][]string
+}
+
+// NewTitleAliases returns aliases where every group lists titles of the same
+// film, e.g. {"Dune: Part Two", "Dune: Teil Zwei"}.
+func NewTitleAliases(groups [][]string) *TitleAliases {
+	a := &TitleAliases{}
+	for _, group := range groups {
+		var normalised []string
+		for _, title := range group {
+			if words := Tokenize(title); len(words) > 0 {
+				normalised = append(normalised, strings.Join(words, " "))
+			}
+		}
+		if len(normalised) > 1 {
+			a.groups = append(a.groups, normalised)
+		}
+	}
+	return a
+}
+
+// Variants returns the normalised title followed by the titles it is known
+// under. An alias also applies if it is only part of the title, so
+// "Dune: Teil Zwei (OmU)" has the variant "dune part two omu".
+func (a *TitleAliases) Variants(title string) []string {
+	normalised := strings.Join(Tokenize(title), " ")
+	variants := []string{normalised}
+	if a == nil {
+		return variants
+	}
+
+	padded := " " + normalised + " "
+	for _, group := range a.groups {
+		for _, alias := range group {
+			if !strings.Contains(padded, " "+alias+" ") {
+				continue
+			}
+			for _, other := range group {
+				if other == alias {
+					continue
+				}
+				variant := strings.TrimSpace(strings.Replace(padded, " "+alias+" ", " "+other+" ", 1))
+				variants = append(variants, variant)
+			}
+			break
+		}
+	}
+
+	return variants
+}
+
+// TitleSimilarity is TitleSimilarity of the best matching variant of title.
+func (a *TitleAliases) TitleSimilarity(query, title string) float64 {
+	q := Tokenize(query)
+	if len(q) == 0 {
+		return 0
+	}
+
+	var best float64
+	for _, variant := range a.Variants(title) {
+		if variant == "" {
+			continue
+		}
+		best = max(best, tokenSimilarity(q, strings.Fields(variant)))
+	}
+	return best
+}
+
+// TitleFilter matches screenings whose title or one of its aliases is
+// similar to query by at least TitleMatchThreshold. aliases may be nil.
+func TitleFilter(query string, aliases *TitleAliases) Filter {
+	return func(s Screening) bool {
+		return aliases.TitleSimilarity(query, s.Title) >= TitleMatchThreshold
+	}
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kino", "", 4},
+		{"", "kino", 4},
+		{"kitten", "sitting", 3},
+		{"dune", "dnue", 1},
+		{"ca", "abc", 3},
+		{"mörder", "morder", 1},
+		{"metropolis", "metropolis", 0},
+	}
+
+	for _, tt := range tests {
+		if got := EditDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func testAliases() *TitleAliases {
+	return NewTitleAliases([][]string{
+		{"Dune: Part Two", "Dune: Teil Zwei"},
+		{"Anatomy of a Fall", "Anatomie d'une chute", "Anatomie eines Falls"},
+		{"The Zone of Interest", "Zone of Interest"},
+		{"Ignored"},
+	})
+}
+
+func TestTitleAliases_Variants(t *testing.T) {
+	tests := []struct {
+		title string
+		want  []string
+	}{
+		{"Dune: Teil Zwei", []string{"dune teil zwei", "dune part two"}},
+		{"Dune: Teil Zwei (OmU)", []string{"dune teil zwei omu", "dune part two omu"}},
+		{"Anatomie d’une chute", []string{"anatomie d une chute", "anatomy of a fall", "anatomie eines falls"}},
+		{"Dune", []string{"dune"}},
+		{"Ignored", []string{"ignored"}},
+	}
+
+	aliases := testAliases()
+	for _, tt := range tests {
+		if got := aliases.Variants(tt.title); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Variants(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+
+	var none *TitleAliases
+	if got := none.Variants("Dune: Teil Zwei"); !reflect.DeepEqual(got, []string{"dune teil zwei"}) {
+		t.Errorf("nil Variants() = %q", got)
+	}
+}
+
+func TestTitleFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		title string
+		want  bool
+	}{
+		// exact, case and diacritics
+		{"Metropolis", "Metropolis", true},
+		{"amelie", "Die fabelhafte Welt der Amélie", true},
+		{"ANATOMIE EINES FALLS", "Anatomie eines Falls", true},
+		// typos
+		{"Metropolsi", "Metropolis", true},
+		{"Oppenhiemer", "Oppenheimer", true},
+		{"Blechtromel", "Die Blechtrommel", true},
+		{"Dnue Part Two", "Dune: Part Two", true},
+		// aliases
+		{"Dune Part Two", "Dune: Teil Zwei", true},
+		{"dune part 2", "Dune: Teil Zwei", true},
+		{"Anatomy of a Fall", "Anatomie eines Falls (OmU)", true},
+		{"Zone of Interest", "The Zone of Interest", true},
+		// no match
+		{"Dune", "Dunkirk", false},
+		{"Alien", "Aliens", true},
+		{"Cat", "Car", false},
+		{"Barbie", "Oppenheimer", false},
+		{"", "Metropolis", false},
+	}
+
+	aliases := testAliases()
+	for _, tt := range tests {
+		s := Screening{Title: tt.title}
+		if got := TitleFilter(tt.query, aliases)(s); got != tt.want {
+			t.Errorf("TitleFilter(%q) on %q = %v (similarity %.2f), want %v",
+				tt.query, tt.title, got, aliases.TitleSimilarity(tt.query, tt.title), tt.want)
+		}
+	}
+}
+
+func TestTitleSimilarity_Order(t *testing.T) {
+	// better matches score higher
+	titles := []string{
+		"Metropolis",
+		"Metropolis (restaurierte Fassung)",
+		"Metropolia",
+		"Metro Manila",
+	}
+
+	var last float64 = 2
+	for _, title := range titles {
+		got := TitleSimilarity("metropolis", title)
+		if got >= last {
+			t.Errorf("TitleSimilarity(metropolis, %q) = %.2f, want below %.2f", title, got, last)
+		}
+		last = got
+	}
+}
+
+func TestSearchIndex_Fuzzy(t *testing.T) {
+	idx := NewSearchIndex()
+	idx.SetAliases(testAliases())
+	for _, s := range []Screening{
+		{ID: "dune", Title: "Dune: Teil Zwei"},
+		{ID: "dunkirk", Title: "Dunkirk"},
+		{ID: "doc", Title: "Kino der Wüste", Description: "Über Dune, Lawrence und andere."},
+	} {
+		idx.Add(s)
+	}
+
+	tests := []struct {
+		query string
+		want  []ScreeningID
+	}{
+		{"Dune Part Two", []ScreeningID{"dune"}},
+		{"dune", []ScreeningID{"dune", "doc"}},
+		{"dunkrik", []ScreeningID{"dunkirk"}},
+	}
+
+	for _, tt := range tests {
+		var got []ScreeningID
+		for _, r := range idx.Search(tt.query) {
+			got = append(got, r.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Order is a sort order of screenings.
+type Order string
+
+const (
+	// OrderStart sorts by start time, then cinema and title. It is the
+	// default order of Fetch.
+	OrderStart Order = "start"
+	OrderTitle Order = "title"
+	// OrderCinema sorts by cinema, the screenings of a cinema by start.
+	OrderCinema Order = "cinema"
+	// OrderRuntime sorts shortest first, unknown runtimes last.
+	OrderRuntime Order = "runtime"
+	// OrderAdded sorts the screenings stored most recently first.
+	OrderAdded Order = "added"
+)
+
+// AllOrders lists all orders in display order.
+var AllOrders = []Order{OrderStart, OrderTitle, OrderCinema, OrderRuntime, OrderAdded}
+
+var orderLabels = map[Order]string{
+	OrderStart:   "Start time",
+	OrderTitle:   "Title",
+	OrderCinema:  "Cinema",
+	OrderRuntime: "Runtime",
+	OrderAdded:   "Newly added",
+}
+
+// ParseOrder returns the order with the given name, ok is false for unknown
+// names.
+func ParseOrder(name string) (order Order, ok bool) {
+	order = Order(name)
+	_, ok = orderLabels[order]
+	return order, ok
+}
+
+// Label returns the human readable name of the order.
+func (o Order) Label() string {
+	if label, ok := orderLabels[o]; ok {
+		return label
+	}
+	return string(o)
+}
+
+// Compare compares screenings in the order o, OrderStart for unknown
+// orders. Screenings equal in o are compared by start, cinema and title so
+// that the order is deterministic.
+func (o Order) Compare(a, b Screening) int {
+	var c int
+	switch o {
+	case OrderTitle:
+		c = strings.Compare(Normalize(a.Title), Normalize(b.Title))
+	case OrderCinema:
+		c = strings.Compare(a.Cinema, b.Cinema)
+	case OrderRuntime:
+		c = cmp.Compare(runtimeKey(a), runtimeKey(b))
+	case OrderAdded:
+		c = b.AddedAt.Compare(a.AddedAt)
+	}
+	return cmp.Or(
+		c,
+		a.Start.Compare(b.Start),
+		strings.Compare(a.Cinema, b.Cinema),
+		strings.Compare(a.Title, b.Title),
+	)
+}
+
+// runtimeKey sorts unknown runtimes after all known ones.
+func runtimeKey(s Screening) int64 {
+	if s.Duration <= 0 {
+		return 1<<63 - 1
+	}
+	return int64(s.Duration)
+}
+
+// Sort sorts screenings in the order o.
+func (o Order) Sort(screenings []Screening) {
+	slices.SortFunc(screenings, o.Compare)
+}
+
+// Page selects part of the sorted results of Storage.Fetch and
+// Storage.Search.
+type Page struct {
+	// Order of the results. Empty means OrderStart for Fetch and best
+	// matches first for Search.
+	Order  Order
+	Offset int
+	// Limit is the maximum number of results, zero means no limit.
+	Limit int
+}
+
+// Slice returns the part of sorted screenings selected by p.
+func (p Page) Slice(screenings []Screening) []Screening {
+	offset := min(max(p.Offset, 0), len(screenings))
+	screenings = screenings[offset:]
+	if p.Limit > 0 && p.Limit < len(screenings) {
+		screenings = screenings[:p.Limit]
+	}
+	return screenings
+}
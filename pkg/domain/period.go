@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+// Period is a named range of days relative to today, like "this weekend".
+type Period string
+
+const (
+	PeriodToday    Period = "today"
+	PeriodTomorrow Period = "tomorrow"
+	// PeriodThisWeekend is the coming Saturday and Sunday, or the rest of
+	// the weekend on weekends.
+	PeriodThisWeekend Period = "this-weekend"
+	// PeriodNext7Days is today and the six days after it.
+	PeriodNext7Days Period = "next-7-days"
+)
+
+// AllPeriods lists all periods in display order.
+var AllPeriods = []Period{PeriodToday, PeriodTomorrow, PeriodThisWeekend, PeriodNext7Days}
+
+var periodLabels = map[Period]string{
+	PeriodToday:       "Today",
+	PeriodTomorrow:    "Tomorrow",
+	PeriodThisWeekend: "This weekend",
+	PeriodNext7Days:   "Next 7 days",
+}
+
+// ParsePeriod returns the period with the given name, ok is false for
+// unknown names.
+func ParsePeriod(name string) (period Period, ok bool) {
+	period = Period(name)
+	_, ok = periodLabels[period]
+	return period, ok
+}
+
+// Label returns the human readable name of the period.
+func (p Period) Label() string {
+	if label, ok := periodLabels[p]; ok {
+		return label
+	}
+	return string(p)
+}
+
+// Days returns the first and last cinema day of the period as seen at now,
+// both at midnight in Berlin.
+func (p Period) Days(days CinemaDays, now time.Time) (first, last time.Time) {
+	today := days.Day(now)
+	switch p {
+	case PeriodTomorrow:
+		tomorrow := today.AddDate(0, 0, 1)
+		return tomorrow, tomorrow
+	case PeriodThisWeekend:
+		switch today.Weekday() {
+		case time.Saturday:
+			return today, today.AddDate(0, 0, 1)
+		case time.Sunday:
+			return today, today
+		default:
+			saturday := today.AddDate(0, 0, int(time.Saturday-today.Weekday()))
+			return saturday, saturday.AddDate(0, 0, 1)
+		}
+	case PeriodNext7Days:
+		return today, today.AddDate(0, 0, 6)
+	default:
+		return today, today
+	}
+}
+
+// PeriodFilter matches screenings in the period p as seen at now.
+func (c CinemaDays) PeriodFilter(p Period, now time.Time) Filter {
+	return c.DateRangeFilter(p.Days(c, now))
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriod_Days(t *testing.T) {
+	date := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 0, 0, 0, 0, Berlin)
+	}
+
+	tests := []struct {
+		period      Period
+		now         time.Time
+		first, last time.Time
+	}{
+		// Wednesday
+		{PeriodToday, time.Date(2025, 3, 5, 10, 0, 0, 0, Berlin), date(3, 5), date(3, 5)},
+		{PeriodTomorrow, time.Date(2025, 3, 5, 10, 0, 0, 0, Berlin), date(3, 6), date(3, 6)},
+		{PeriodThisWeekend, time.Date(2025, 3, 5, 10, 0, 0, 0, Berlin), date(3, 8), date(3, 9)},
+		{PeriodNext7Days, time.Date(2025, 3, 5, 10, 0, 0, 0, Berlin), date(3, 5), date(3, 11)},
+		// Saturday and Sunday
+		{PeriodThisWeekend, time.Date(2025, 3, 8, 23, 0, 0, 0, Berlin), date(3, 8), date(3, 9)},
+		{PeriodThisWeekend, time.Date(2025, 3, 9, 12, 0, 0, 0, Berlin), date(3, 9), date(3, 9)},
+		// 23:30 UTC on Friday is already Saturday in Berlin
+		{PeriodToday, time.Date(2025, 3, 7, 23, 30, 0, 0, time.UTC), date(3, 8), date(3, 8)},
+		// the week with the change to summer time
+		{PeriodNext7Days, time.Date(2025, 3, 27, 12, 0, 0, 0, Berlin), date(3, 27), date(4, 2)},
+	}
+
+	for _, tt := range tests {
+		first, last := tt.period.Days(CinemaDays{}, tt.now)
+		if !first.Equal(tt.first) || !last.Equal(tt.last) {
+			t.Errorf("%s.Days(%v) = %v, %v, want %v, %v", tt.period, tt.now, first, last, tt.first, tt.last)
+		}
+	}
+}
+
+func TestTimeFilters(t *testing.T) {
+	at := func(d, hour, minute int) Screening {
+		return Screening{Start: time.Date(2025, 3, d, hour, minute, 0, 0, Berlin)}
+	}
+	clockAt := func(s string) time.Duration {
+		c, err := ParseClock(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	var days CinemaDays
+	tests := []struct {
+		name      string
+		filter    Filter
+		screening Screening
+		want      bool
+	}{
+		{"in range", days.DateRangeFilter(time.Date(2025, 3, 7, 0, 0, 0, 0, Berlin), time.Date(2025, 3, 9, 0, 0, 0, 0, Berlin)), at(9, 23, 59), true},
+		{"after range", days.DateRangeFilter(time.Date(2025, 3, 7, 0, 0, 0, 0, Berlin), time.Date(2025, 3, 9, 0, 0, 0, 0, Berlin)), at(10, 0, 0), false},
+		{"range in UTC", days.DateRangeFilter(time.Date(2025, 3, 8, 0, 0, 0, 0, Berlin), time.Date(2025, 3, 8, 0, 0, 0, 0, Berlin)), Screening{Start: time.Date(2025, 3, 7, 23, 30, 0, 0, time.UTC)}, true},
+		{"between", days.StartsBetweenFilter(clockAt("18:00"), clockAt("21:00")), at(7, 20, 59), true},
+		{"between end", days.StartsBetweenFilter(clockAt("18:00"), clockAt("21:00")), at(7, 21, 0), false},
+		{"between start", days.StartsBetweenFilter(clockAt("18:00"), clockAt("21:00")), at(7, 18, 0), true},
+		{"across midnight late", days.StartsBetweenFilter(clockAt("22:00"), clockAt("02:00")), at(7, 23, 0), true},
+		{"across midnight early", days.StartsBetweenFilter(clockAt("22:00"), clockAt("02:00")), at(7, 1, 30), true},
+		{"across midnight outside", days.StartsBetweenFilter(clockAt("22:00"), clockAt("02:00")), at(7, 12, 0), false},
+		{"clock in UTC", days.StartsAfterFilter(clockAt("20:00")), Screening{Start: time.Date(2025, 7, 1, 18, 30, 0, 0, time.UTC)}, true},
+		{"weekend", days.WeekdayFilter(time.Saturday, time.Sunday), at(8, 12, 0), true},
+		{"weekday", days.WeekdayFilter(time.Saturday, time.Sunday), at(7, 12, 0), false},
+		{"weekday in UTC", days.WeekdayFilter(time.Saturday), Screening{Start: time.Date(2025, 3, 7, 23, 30, 0, 0, time.UTC)}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter(tt.screening); got != tt.want {
+			t.Errorf("%s: filter(%v) = %v, want %v", tt.name, tt.screening.Start, got, tt.want)
+		}
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	for name, want := range map[string]time.Weekday{
+		"Tuesday": time.Tuesday, "dienstag": time.Tuesday, " tue ": time.Tuesday, "SAMSTAG": time.Saturday,
+	} {
+		if got, err := ParseWeekday(name); err != nil || got != want {
+			t.Errorf("ParseWeekday(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseWeekday("caturday"); err == nil {
+		t.Error("ParseWeekday(caturday) succeeded")
+	}
+}
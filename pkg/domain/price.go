@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Money is an amount in cents.
+type Money int64
+
+// ParseMoney parses amounts like "9.50", "9,50" or "9".
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "€"))
+	f, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return MoneyFromFloat(f), nil
+}
+
+// MoneyFromFloat converts an amount in the main unit, e.g. 9.5 euros.
+func MoneyFromFloat(f float64) Money {
+	return Money(math.Round(f * 100))
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%d.%02d", m/100, m%100)
+}
+
+// Prices of tickets for a screening. Prices with an empty currency are
+// unknown, known prices of zero are free screenings.
+type Prices struct {
+	// Currency is an ISO 4217 code like "EUR".
+	Currency string
+	Regular  Money
+	// Reduced is the price for students, seniors etc. Zero if there is no
+	// reduction.
+	Reduced Money
+	// Special is the price on discount days like the weekly cinema day. It
+	// applies on SpecialDays only.
+	Special     Money
+	SpecialDays []time.Weekday
+}
+
+// Known reports whether the prices are known.
+func (p Prices) Known() bool {
+	return p.Currency != ""
+}
+
+// On returns the regular price of a screening starting at t, which is the
+// special price on special days.
+func (p Prices) On(t time.Time) Money {
+	if p.Special > 0 && slices.Contains(p.SpecialDays, t.In(Berlin).Weekday()) {
+		return p.Special
+	}
+	return p.Regular
+}
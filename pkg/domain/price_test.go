@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{"9.50", 950, false},
+		{"9,5", 950, false},
+		{" 12 € ", 1200, false},
+		{"0", 0, false},
+		{"-1", 0, true},
+		{"cheap", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestPrices_On(t *testing.T) {
+	p := Prices{Currency: "EUR", Regular: 1200, Special: 800, SpecialDays: []time.Weekday{time.Tuesday}}
+
+	// 23:30 UTC on Monday is already Tuesday in Berlin
+	tuesday := time.Date(2025, 12, 1, 23, 30, 0, 0, time.UTC)
+	if got := p.On(tuesday); got != 800 {
+		t.Errorf("On(Tuesday) = %v, want 8.00", got)
+	}
+	if got := p.On(tuesday.Add(24 * time.Hour)); got != 1200 {
+		t.Errorf("On(Wednesday) = %v, want 12.00", got)
+	}
+}
+
+func TestMaxPriceFilter(t *testing.T) {
+	monday := time.Date(2025, 12, 1, 20, 0, 0, 0, Berlin)
+	tuesday := monday.AddDate(0, 0, 1)
+	prices := Prices{Currency: "EUR", Regular: 1200, Special: 800, SpecialDays: []time.Weekday{time.Tuesday}}
+
+	tests := []struct {
+		name string
+		s    Screening
+		want bool
+	}{
+		{"too expensive", Screening{Start: monday, Prices: prices}, false},
+		{"cinema day", Screening{Start: tuesday, Prices: prices}, true},
+		{"free", Screening{Start: monday, Prices: Prices{Currency: "EUR"}}, true},
+		{"unknown", Screening{Start: monday}, true},
+	}
+
+	filter := MaxPriceFilter(1000)
+	for _, tt := range tests {
+		if got := filter(tt.s); got != tt.want {
+			t.Errorf("%s: MaxPriceFilter(10.00) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,594 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Query is a parsed filter expression like
+//
+//	cinema:"Delphi Lux" OR cinema:Babylon after:19:00 lang:OV -title:horror
+//
+// Terms are free text words or field:value pairs, values containing spaces
+// are quoted. Terms next to each other must all match, as if joined by AND.
+// OR matches either side and binds weaker than AND, so the example above
+// means Delphi Lux, or Babylon after 19:00 in OV without horror. NOT or a
+// leading "-" negates a term, parentheses group terms. AND, OR and NOT are
+// only operators when written in capitals.
+//
+// Fields are cinema (part of a cinema name or a cinema group), title, lang,
+// tag, date (a day like 2025-03-08 or a range like 2025-03-07..2025-03-09),
+// period (today, tomorrow, this-weekend or next-7-days), day (weekday names,
+// weekend or weekdays), after, before, ends_before, min_runtime and
+// max_runtime (minutes or a duration like 1h40m), max_price, director, cast
+// and is (available or soldout). Days and times are in Berlin.
+type Query struct {
+	// Filter matches the screenings selected by the expression apart from
+	// Text.
+	Filter Filter
+
+	// Text holds the free text words that every match must contain. They are
+	// kept apart from Filter so that results can be ranked by them, e.g.
+	// with a SearchIndex.
+	Text string
+}
+
+// Match returns a filter matching the screenings selected by the whole
+// expression, for users without a SearchIndex.
+func (q Query) Match() Filter {
+	if q.Text == "" {
+		return q.Filter
+	}
+	return And(q.Filter, SearchFilter(q.Text))
+}
+
+// ParseError is a syntax error in a query.
+type ParseError struct {
+	// Pos is the byte offset of the error in the query.
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid query at column %d: %s", e.Pos+1, e.Msg)
+}
+
+// queryMaxDepth limits the nesting of parentheses and negations.
+const queryMaxDepth = 32
+
+// QueryParser parses queries.
+type QueryParser struct {
+	// TitleAliases are used by the title field. Optional.
+	TitleAliases *TitleAliases
+
+	// Now is the time periods like "today" are relative to. Defaults to the
+	// current time.
+	Now time.Time
+
+	// CinemaGroups can be named in the cinema field. Optional.
+	CinemaGroups CinemaGroups
+
+	// CinemaDays is used by the fields for dates, days and times of day.
+	// The zero value begins cinema days at midnight.
+	CinemaDays CinemaDays
+}
+
+// ParseQuery parses input without title aliases.
+func ParseQuery(input string) (Query, error) {
+	return QueryParser{}.Parse(input)
+}
+
+// Parse parses input. An empty input matches everything.
+func (p QueryParser) Parse(input string) (Query, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return Query{}, err
+	}
+
+	ps := &queryParse{tokens: tokens}
+	if ps.peek().kind == tokenEOF {
+		return Query{Filter: And()}, nil
+	}
+
+	root, err := ps.or(0)
+	if err != nil {
+		return Query{}, err
+	}
+	if t := ps.peek(); t.kind != tokenEOF {
+		return Query{}, &ParseError{Pos: t.pos, Msg: "unexpected ')'"}
+	}
+
+	// Free text words at the top level go to Text, everything else to Filter.
+	conjuncts := []*queryNode{root}
+	if root.op == queryAnd {
+		conjuncts = root.children
+	}
+
+	var (
+		filters []Filter
+		text    []string
+	)
+	for _, n := range conjuncts {
+		if n.op == queryTerm && n.token.key == "" {
+			text = append(text, n.token.value)
+			continue
+		}
+		f, err := p.compile(n)
+		if err != nil {
+			return Query{}, err
+		}
+		filters = append(filters, f)
+	}
+
+	return Query{Filter: And(filters...), Text: strings.Join(text, " ")}, nil
+}
+
+type queryTokenKind int
+
+const (
+	tokenEOF queryTokenKind = iota
+	tokenTerm
+	tokenOpen
+	tokenClose
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type queryToken struct {
+	kind queryTokenKind
+	pos  int
+	// key and value of a term, key is empty for free text
+	key   string
+	value string
+}
+
+func lexQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(input) {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenOpen, pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenClose, pos: i})
+			i++
+		case r == '-':
+			// only negates a term directly after it, a dash on its own
+			// like in "Mission: Impossible - Dead Reckoning" separates
+			next, _ := utf8.DecodeRuneInString(input[i+1:])
+			if i+1 < len(input) && !unicode.IsSpace(next) && next != ')' {
+				tokens = append(tokens, queryToken{kind: tokenNot, pos: i})
+			}
+			i++
+		case r == '"':
+			value, end, err := lexQuoted(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, queryToken{kind: tokenTerm, pos: i, value: value})
+			i = end
+		default:
+			start := i
+			for i < len(input) {
+				r, size := utf8.DecodeRuneInString(input[i:])
+				if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' {
+					break
+				}
+				i += size
+			}
+			word := input[start:i]
+
+			switch word {
+			case "AND":
+				tokens = append(tokens, queryToken{kind: tokenAnd, pos: start})
+				continue
+			case "OR":
+				tokens = append(tokens, queryToken{kind: tokenOr, pos: start})
+				continue
+			case "NOT":
+				tokens = append(tokens, queryToken{kind: tokenNot, pos: start})
+				continue
+			}
+
+			key, value, hasKey := strings.Cut(word, ":")
+			if hasKey && i < len(input) && input[i] == '"' && value == "" {
+				quoted, end, err := lexQuoted(input, i)
+				if err != nil {
+					return nil, err
+				}
+				value, i = quoted, end
+			}
+			token, err := lexTerm(key, value, word, hasKey, start)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
+		}
+	}
+
+	return append(tokens, queryToken{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// lexQuoted reads the quoted string starting at input[start] and returns it
+// without quotes and the offset after the closing quote.
+func lexQuoted(input string, start int) (value string, end int, err error) {
+	closing := strings.IndexByte(input[start+1:], '"')
+	if closing < 0 {
+		return "", 0, &ParseError{Pos: start, Msg: "missing closing quote"}
+	}
+	end = start + 1 + closing
+	return input[start+1 : end], end + 1, nil
+}
+
+// lexTerm makes a term token of a word. Words that merely contain a colon,
+// like "Star Wars: Episode I", are free text.
+func lexTerm(key, value, word string, hasKey bool, pos int) (queryToken, error) {
+	free := queryToken{kind: tokenTerm, pos: pos, value: word}
+	if !hasKey {
+		return free, nil
+	}
+
+	field := strings.ToLower(key)
+	if _, ok := queryFields[field]; !ok {
+		if value == "" || !isLetters(key) {
+			return free, nil
+		}
+		return queryToken{}, &ParseError{
+			Pos: pos,
+			Msg: fmt.Sprintf("unknown field %q, known fields are %s", key, strings.Join(queryFieldNames(), ", ")),
+		}
+	}
+	if value == "" {
+		return queryToken{}, &ParseError{Pos: pos, Msg: fmt.Sprintf("missing value for %s", field)}
+	}
+
+	return queryToken{kind: tokenTerm, pos: pos, key: field, value: value}, nil
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '_' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+type queryOp int
+
+const (
+	queryTerm queryOp = iota
+	queryAnd
+	queryOr
+	queryNot
+)
+
+type queryNode struct {
+	op       queryOp
+	token    queryToken
+	children []*queryNode
+}
+
+type queryParse struct {
+	tokens []queryToken
+	next   int
+}
+
+func (ps *queryParse) peek() queryToken {
+	return ps.tokens[ps.next]
+}
+
+func (ps *queryParse) advance() queryToken {
+	t := ps.tokens[ps.next]
+	if t.kind != tokenEOF {
+		ps.next++
+	}
+	return t
+}
+
+func (ps *queryParse) or(depth int) (*queryNode, error) {
+	first, err := ps.and(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*queryNode{first}
+	for ps.peek().kind == tokenOr {
+		ps.advance()
+		next, err := ps.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, next)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &queryNode{op: queryOr, children: children}, nil
+}
+
+func (ps *queryParse) and(depth int) (*queryNode, error) {
+	first, err := ps.unary(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*queryNode{first}
+	for {
+		switch ps.peek().kind {
+		case tokenAnd:
+			ps.advance()
+		case tokenTerm, tokenOpen, tokenNot:
+		default:
+			if len(children) == 1 {
+				return first, nil
+			}
+			return &queryNode{op: queryAnd, children: children}, nil
+		}
+
+		next, err := ps.unary(depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, next)
+	}
+}
+
+func (ps *queryParse) unary(depth int) (*queryNode, error) {
+	t := ps.peek()
+	if depth > queryMaxDepth {
+		return nil, &ParseError{Pos: t.pos, Msg: "query is nested too deeply"}
+	}
+
+	switch t.kind {
+	case tokenTerm:
+		ps.advance()
+		return &queryNode{op: queryTerm, token: t}, nil
+	case tokenNot:
+		ps.advance()
+		if k := ps.peek().kind; k != tokenTerm && k != tokenOpen && k != tokenNot {
+			return nil, &ParseError{Pos: t.pos, Msg: "NOT must be followed by a term"}
+		}
+		operand, err := ps.unary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &queryNode{op: queryNot, children: []*queryNode{operand}}, nil
+	case tokenOpen:
+		ps.advance()
+		if ps.peek().kind == tokenClose {
+			return nil, &ParseError{Pos: t.pos, Msg: "empty parentheses"}
+		}
+		inner, err := ps.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if ps.peek().kind != tokenClose {
+			return nil, &ParseError{Pos: t.pos, Msg: "missing ')' for this '('"}
+		}
+		ps.advance()
+		return inner, nil
+	case tokenClose:
+		return nil, &ParseError{Pos: t.pos, Msg: "unexpected ')'"}
+	case tokenAnd, tokenOr:
+		op := "AND"
+		if t.kind == tokenOr {
+			op = "OR"
+		}
+		return nil, &ParseError{Pos: t.pos, Msg: op + " must stand between two terms"}
+	default:
+		return nil, &ParseError{Pos: t.pos, Msg: "missing term at end of query"}
+	}
+}
+
+func (p QueryParser) compile(n *queryNode) (Filter, error) {
+	switch n.op {
+	case queryTerm:
+		if n.token.key == "" {
+			return SearchFilter(n.token.value), nil
+		}
+		f, err := queryFields[n.token.key](p, n.token.value)
+		if err != nil {
+			return nil, &ParseError{Pos: n.token.pos, Msg: fmt.Sprintf("%s: %v", n.token.key, err)}
+		}
+		return f, nil
+	case queryNot:
+		f, err := p.compile(n.children[0])
+		if err != nil {
+			return nil, err
+		}
+		return Not(f), nil
+	default:
+		filters := make([]Filter, len(n.children))
+		for i, child := range n.children {
+			f, err := p.compile(child)
+			if err != nil {
+				return nil, err
+			}
+			filters[i] = f
+		}
+		if n.op == queryOr {
+			return Or(filters...), nil
+		}
+		return And(filters...), nil
+	}
+}
+
+// queryFields maps the fields of the query language to their filters.
+var queryFields = map[string]func(p QueryParser, value string) (Filter, error){
+	"cinema": func(p QueryParser, value string) (Filter, error) {
+		if cinemas, ok := p.CinemaGroups.Lookup(value); ok {
+			return CinemaFilter(cinemas...), nil
+		}
+		return func(s Screening) bool { return containsFolded(s.Cinema, value) }, nil
+	},
+	"title": func(p QueryParser, value string) (Filter, error) {
+		return TitleFilter(value, p.TitleAliases), nil
+	},
+	"lang": func(_ QueryParser, value string) (Filter, error) {
+		return func(s Screening) bool { return strings.EqualFold(s.Language, value) }, nil
+	},
+	"tag": func(_ QueryParser, value string) (Filter, error) {
+		tag, ok := ParseTag(strings.ToLower(value))
+		if !ok {
+			names := make([]string, len(AllTags))
+			for i, t := range AllTags {
+				names[i] = string(t)
+			}
+			return nil, fmt.Errorf("unknown tag %q, known tags are %s", value, strings.Join(names, ", "))
+		}
+		return TagFilter(tag), nil
+	},
+	"date": func(p QueryParser, value string) (Filter, error) {
+		fromStr, toStr, isRange := strings.Cut(value, "..")
+		from, err := time.ParseInLocation(time.DateOnly, fromStr, Berlin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q, want YYYY-MM-DD", fromStr)
+		}
+		if !isRange {
+			return p.CinemaDays.DateRangeFilter(from, from), nil
+		}
+		to, err := time.ParseInLocation(time.DateOnly, toStr, Berlin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q, want YYYY-MM-DD", toStr)
+		}
+		if to.Before(from) {
+			return nil, fmt.Errorf("range %q ends before it starts", value)
+		}
+		return p.CinemaDays.DateRangeFilter(from, to), nil
+	},
+	"period": func(p QueryParser, value string) (Filter, error) {
+		period, ok := ParsePeriod(strings.ToLower(value))
+		if !ok {
+			names := make([]string, len(AllPeriods))
+			for i, p := range AllPeriods {
+				names[i] = string(p)
+			}
+			return nil, fmt.Errorf("unknown period %q, known periods are %s", value, strings.Join(names, ", "))
+		}
+		now := p.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		return p.CinemaDays.PeriodFilter(period, now), nil
+	},
+	"day": func(p QueryParser, value string) (Filter, error) {
+		switch strings.ToLower(value) {
+		case "weekend":
+			return p.CinemaDays.WeekdayFilter(time.Saturday, time.Sunday), nil
+		case "weekdays":
+			return Not(p.CinemaDays.WeekdayFilter(time.Saturday, time.Sunday)), nil
+		}
+		var days []time.Weekday
+		for _, name := range strings.Split(value, ",") {
+			day, err := ParseWeekday(name)
+			if err != nil {
+				return nil, err
+			}
+			days = append(days, day)
+		}
+		return p.CinemaDays.WeekdayFilter(days...), nil
+	},
+	"after": func(p QueryParser, value string) (Filter, error) {
+		c, err := ParseClock(value)
+		if err != nil {
+			return nil, err
+		}
+		return p.CinemaDays.StartsAfterFilter(c), nil
+	},
+	"before": func(p QueryParser, value string) (Filter, error) {
+		c, err := ParseClock(value)
+		if err != nil {
+			return nil, err
+		}
+		return p.CinemaDays.StartsBeforeFilter(c), nil
+	},
+	"ends_before": func(p QueryParser, value string) (Filter, error) {
+		c, err := ParseClock(value)
+		if err != nil {
+			return nil, err
+		}
+		return p.CinemaDays.EndsBeforeFilter(c), nil
+	},
+	"min_runtime": func(_ QueryParser, value string) (Filter, error) {
+		min, err := ParseRuntime(value)
+		if err != nil {
+			return nil, err
+		}
+		return DurationFilter(min, 0), nil
+	},
+	"max_runtime": func(_ QueryParser, value string) (Filter, error) {
+		max, err := ParseRuntime(value)
+		if err != nil {
+			return nil, err
+		}
+		return DurationFilter(0, max), nil
+	},
+	"max_price": func(_ QueryParser, value string) (Filter, error) {
+		max, err := ParseMoney(value)
+		if err != nil {
+			return nil, err
+		}
+		return MaxPriceFilter(max), nil
+	},
+	"director": func(_ QueryParser, value string) (Filter, error) {
+		return func(s Screening) bool { return containsFolded(s.Director, value) }, nil
+	},
+	"cast": func(_ QueryParser, value string) (Filter, error) {
+		return func(s Screening) bool {
+			return slices.ContainsFunc(s.Cast, func(name string) bool {
+				return containsFolded(name, value)
+			})
+		}, nil
+	},
+	"is": func(_ QueryParser, value string) (Filter, error) {
+		switch strings.ToLower(value) {
+		case "available":
+			return AvailableFilter(), nil
+		case "soldout", "sold-out":
+			return Not(AvailableFilter()), nil
+		default:
+			return nil, fmt.Errorf("unknown state %q, want available or soldout", value)
+		}
+	},
+}
+
+// queryFieldAliases are alternative names of fields.
+var queryFieldAliases = map[string]string{
+	"language": "lang",
+	"price":    "max_price",
+	"runtime":  "max_runtime",
+}
+
+func init() {
+	for alias, field := range queryFieldAliases {
+		queryFields[alias] = queryFields[field]
+	}
+}
+
+func queryFieldNames() []string {
+	var names []string
+	for name := range queryFields {
+		if _, ok := queryFieldAliases[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// containsFolded reports whether s contains substr, ignoring case and
+// diacritics.
+func containsFolded(s, substr string) bool {
+	return strings.Contains(Normalize(s), Normalize(substr))
+}
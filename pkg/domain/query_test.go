@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func queryFixtures(t *testing.T) []Screening {
+	t.Helper()
+	evening := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, Berlin)
+	}
+	return []Screening{
+		{ID: "delphi", Title: "Anatomie eines Falls", Cinema: "Delphi LUX", Language: "OmU", Start: evening(7, 18, 0), Duration: 151 * time.Minute, Director: "Justine Triet"},
+		{ID: "babylon-late", Title: "Evil Dead", Cinema: "Kino Babylon", Language: "OV", Start: evening(7, 21, 30), Genres: []string{"Horror"}, Description: "Horror classic."},
+		{ID: "babylon-ov", Title: "Metropolis", Cinema: "Kino Babylon", Language: "OV", Start: evening(8, 20, 0), Tags: Tags{Tag35mm}, Director: "Fritz Lang"},
+		{ID: "babylon-early", Title: "Pippi Langstrumpf", Cinema: "Kino Babylon", Language: "DF", Start: evening(8, 15, 0), Tags: Tags{TagKids}, Availability: AvailabilitySoldOut},
+		{ID: "yorck", Title: "Oppenheimer", Cinema: "Yorck Kino", Language: "OV", Start: evening(9, 19, 30), Duration: 180 * time.Minute, Cast: []string{"Cillian Murphy"}, Prices: Prices{Currency: "EUR", Regular: 1200}},
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []ScreeningID
+		text  string
+	}{
+		{"", []ScreeningID{"delphi", "babylon-late", "babylon-ov", "babylon-early", "yorck"}, ""},
+		{"cinema:babylon", []ScreeningID{"babylon-late", "babylon-ov", "babylon-early"}, ""},
+		{`cinema:"Delphi Lux" OR cinema:Babylon AND after:19:00 AND lang:OV AND -title:"Evil Dead"`, []ScreeningID{"delphi", "babylon-ov"}, ""},
+		{`(cinema:"delphi lux" OR cinema:babylon) after:19:00`, []ScreeningID{"babylon-late", "babylon-ov"}, ""},
+		{"NOT cinema:babylon", []ScreeningID{"delphi", "yorck"}, ""},
+		{"-(lang:ov OR lang:omu)", []ScreeningID{"babylon-early"}, ""},
+		{"before:18:00", []ScreeningID{"babylon-early"}, ""},
+		{"tag:35MM", []ScreeningID{"babylon-ov"}, ""},
+		{"date:2025-03-08", []ScreeningID{"babylon-ov", "babylon-early"}, ""},
+		{"is:soldout", []ScreeningID{"babylon-early"}, ""},
+		{"is:available cinema:babylon", []ScreeningID{"babylon-late", "babylon-ov"}, ""},
+		{"max_price:10", []ScreeningID{"delphi", "babylon-late", "babylon-ov", "babylon-early"}, ""},
+		{"director:lang", []ScreeningID{"babylon-ov"}, ""},
+		{"cast:murphy", []ScreeningID{"yorck"}, ""},
+		{"language:omu", []ScreeningID{"delphi"}, ""},
+		{"title:metropolsi", []ScreeningID{"babylon-ov"}, ""},
+		{"date:2025-03-08..2025-03-09", []ScreeningID{"babylon-ov", "babylon-early", "yorck"}, ""},
+		{"day:weekend", []ScreeningID{"babylon-ov", "babylon-early", "yorck"}, ""},
+		{"day:fri,sunday", []ScreeningID{"delphi", "babylon-late", "yorck"}, ""},
+		{"day:weekdays", []ScreeningID{"delphi", "babylon-late"}, ""},
+		{"max_runtime:100", []ScreeningID{"babylon-late", "babylon-ov", "babylon-early"}, ""},
+		{"min_runtime:2h", []ScreeningID{"delphi", "babylon-late", "babylon-ov", "babylon-early", "yorck"}, ""},
+		{"runtime:2h30m", []ScreeningID{"babylon-late", "babylon-ov", "babylon-early"}, ""},
+		{"ends_before:21:00", []ScreeningID{"delphi", "babylon-early"}, ""},
+		// free text is kept apart for ranking
+		{"lang Babylon:", []ScreeningID{"delphi", "babylon-late", "babylon-ov", "babylon-early", "yorck"}, "lang Babylon:"},
+		{`horror cinema:babylon`, []ScreeningID{"babylon-late", "babylon-ov", "babylon-early"}, "horror"},
+		{`"evil dead" OR metropolis`, []ScreeningID{"babylon-late", "babylon-ov"}, ""},
+		{`-horror`, []ScreeningID{"delphi", "babylon-ov", "babylon-early", "yorck"}, ""},
+		// a dash on its own is no negation
+		{"evil - dead", []ScreeningID{"delphi", "babylon-late", "babylon-ov", "babylon-early", "yorck"}, "evil dead"},
+		{"metropolis -", []ScreeningID{"delphi", "babylon-late", "babylon-ov", "babylon-early", "yorck"}, "metropolis"},
+		{"Mission: Impossible - Dead Reckoning", []ScreeningID{"delphi", "babylon-late", "babylon-ov", "babylon-early", "yorck"}, "Mission: Impossible Dead Reckoning"},
+		{"oppenheimer and or", []ScreeningID{"delphi", "babylon-late", "babylon-ov", "babylon-early", "yorck"}, "oppenheimer and or"},
+	}
+
+	screenings := queryFixtures(t)
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) error = %v", tt.query, err)
+			continue
+		}
+
+		var got []ScreeningID
+		for _, s := range screenings {
+			if q.Filter(s) {
+				got = append(got, s.ID)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseQuery(%q) matched %q, want %q", tt.query, got, tt.want)
+		}
+		if q.Text != tt.text {
+			t.Errorf("ParseQuery(%q).Text = %q, want %q", tt.query, q.Text, tt.text)
+		}
+	}
+}
+
+func TestQueryParser_Period(t *testing.T) {
+	// Friday, 7 March
+	p := QueryParser{Now: time.Date(2025, 3, 7, 12, 0, 0, 0, Berlin)}
+	q, err := p.Parse("period:this-weekend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []ScreeningID
+	for _, s := range queryFixtures(t) {
+		if q.Filter(s) {
+			got = append(got, s.ID)
+		}
+	}
+	if want := []ScreeningID{"babylon-ov", "babylon-early", "yorck"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("period:this-weekend matched %q, want %q", got, want)
+	}
+}
+
+func TestQueryParser_CinemaDays(t *testing.T) {
+	// Saturday 00:30, the late show of Friday
+	late := Screening{Start: time.Date(2025, 3, 8, 0, 30, 0, 0, Berlin)}
+
+	tests := []struct {
+		query    string
+		rollover time.Duration
+		want     bool
+	}{
+		{"date:2025-03-07 after:22:00", 4 * time.Hour, true},
+		{"date:2025-03-07", 0, false},
+		{"day:friday", 4 * time.Hour, true},
+		{"day:friday", 0, false},
+	}
+
+	for _, tt := range tests {
+		p := QueryParser{CinemaDays: CinemaDays{Rollover: tt.rollover}}
+		q, err := p.Parse(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := q.Filter(late); got != tt.want {
+			t.Errorf("%q with rollover %v matched late show = %v, want %v", tt.query, tt.rollover, got, tt.want)
+		}
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	q, err := ParseQuery("horror cinema:babylon")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []ScreeningID
+	for _, s := range queryFixtures(t) {
+		if q.Match()(s) {
+			got = append(got, s.ID)
+		}
+	}
+	if want := []ScreeningID{"babylon-late"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Match() matched %q, want %q", got, want)
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{`cinema:"Delphi`, 7, "missing closing quote"},
+		{"(cinema:babylon", 0, "missing ')'"},
+		{"cinema:babylon)", 14, "unexpected ')'"},
+		{"()", 0, "empty parentheses"},
+		{"OR cinema:babylon", 0, "OR must stand between two terms"},
+		{"cinema:babylon AND", 18, "missing term"},
+		{"cinema:babylon OR OR lang:ov", 18, "OR must stand between two terms"},
+		{"NOT", 0, "NOT must be followed by a term"},
+		{"cinmea:babylon", 0, `unknown field "cinmea"`},
+		{"cinema:", 0, "missing value for cinema"},
+		{"after:7pm", 0, `after: invalid time "7pm"`},
+		{"lang:ov tag:imaxx", 8, `tag: unknown tag "imaxx"`},
+		{"date:08.03.2025", 0, "date: invalid date"},
+		{"is:cheap", 0, "is: unknown state"},
+		{"date:2025-03-09..2025-03-08", 0, "ends before it starts"},
+		{"period:someday", 0, `period: unknown period "someday"`},
+		{"day:caturday", 0, `day: unknown weekday "caturday"`},
+		{"max_runtime:long", 0, `max_runtime: invalid runtime "long"`},
+		{strings.Repeat("(", 40) + "x" + strings.Repeat(")", 40), 33, "nested too deeply"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("ParseQuery(%q) error = %v, want a ParseError", tt.query, err)
+			continue
+		}
+		if parseErr.Pos != tt.pos || !strings.Contains(parseErr.Msg, tt.msg) {
+			t.Errorf("ParseQuery(%q) error at %d: %q, want at %d: %q", tt.query, parseErr.Pos, parseErr.Msg, tt.pos, tt.msg)
+		}
+	}
+}
+
+func FuzzParseQuery(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		`cinema:"Delphi Lux" OR cinema:Babylon AND after:19:00 AND lang:OV AND -title:horror`,
+		"(a OR b) -(c d) NOT e",
+		`"unclosed`,
+		"tag:35mm date:2025-03-08 max_price:9,50 is:available",
+		"Star Wars: Episode I",
+		"Mission: Impossible - Dead Reckoning",
+		"(a -) - -b",
+		"((((x))))",
+		"- -x",
+	} {
+		f.Add(seed)
+	}
+
+	screenings := []Screening{
+		{Title: "Metropolis", Cinema: "Kino Babylon", Start: time.Date(2025, 3, 8, 20, 0, 0, 0, Berlin)},
+		{},
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		q, err := ParseQuery(input)
+		if err != nil {
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseQuery(%q) error = %v, want a ParseError", input, err)
+			}
+			if parseErr.Pos < 0 || parseErr.Pos > len(input) {
+				t.Fatalf("ParseQuery(%q) error position %d out of range", input, parseErr.Pos)
+			}
+			return
+		}
+
+		for _, s := range screenings {
+			q.Match()(s)
+		}
+	})
+}
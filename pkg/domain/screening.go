@@ -14,12 +14,46 @@ type Screening struct {
 	Duration    time.Duration
 	Cinema      string
 	Language    string
-	Links       ScreeningLinks
-	UpdatedAt   time.Time
+	Director    string
+	Cast        []string
+	Genres      []string
+	// AgeRating is the German age rating, e.g. "FSK 12".
+	AgeRating string
+	Year      int
+	// Country lists the production countries as given by the cinema, e.g.
+	// "I" or "F/BE".
+	Country string
+	// Notes are remarks about the event like guests, Q&As or the series it
+	// belongs to.
+	Notes []string
+	// Format is the projection format like "35mm" or "3D".
+	Format       string
+	Tags         Tags
+	Availability Availability
+	Prices       Prices
+	Links        ScreeningLinks
+	UpdatedAt    time.Time
+	// AddedAt is when the screening was first stored, set by the storage.
+	AddedAt time.Time
+}
+
+// AssumedDuration stands in for unknown durations where an end time is
+// needed. Most features plus trailers end within it.
+const AssumedDuration = 2 * time.Hour
+
+// End returns when the screening ends. known is false if the duration is
+// unknown and the end is estimated with AssumedDuration.
+func (s Screening) End() (end time.Time, known bool) {
+	if s.Duration <= 0 {
+		return s.Start.Add(AssumedDuration), false
+	}
+	return s.Start.Add(s.Duration), true
 }
 
 type ScreeningLinks struct {
-	Details       string
+	Details string
+	// Booking links directly to the ticket shop of the screening.
+	Booking       string
 	ThumbnailLink string
 }
 
@@ -0,0 +1,243 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Search weights of the indexed fields. A match in the title ranks above a
+// match in the people, which ranks above a match in the description.
+const (
+	searchWeightDescription = 1
+	searchWeightPeople      = 3
+	searchWeightTitle       = 5
+)
+
+// foldReplacer spells out letters that do not decompose into a base letter
+// and a diacritic.
+var foldReplacer = strings.NewReplacer("ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d")
+
+// Normalize folds s for comparison: it is lower cased and diacritics are
+// removed, so "Almodóvar" and "almodovar" are equal.
+func Normalize(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, strings.ToLower(s))
+	if err != nil {
+		folded = strings.ToLower(s)
+	}
+	return foldReplacer.Replace(folded)
+}
+
+// Tokenize splits s into normalised words.
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(Normalize(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// SearchFilter matches screenings whose title, description, director or cast
+// contain every word of query. Words match as prefixes, so a query typed
+// halfway already matches. An empty query matches everything.
+//
+// SearchFilter does not rank and is meant for composing with other filters,
+// use a SearchIndex for ranked results.
+func SearchFilter(query string) Filter {
+	words := Tokenize(query)
+	return func(s Screening) bool {
+		terms := searchTerms(s)
+		for _, w := range words {
+			if !slices.ContainsFunc(terms, func(t searchTerm) bool {
+				return strings.HasPrefix(t.word, w)
+			}) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+type searchTerm struct {
+	word   string
+	weight int
+}
+
+// searchTerms returns the indexed words of s with the weight of the field
+// they appear in. A word appearing in several fields is returned once with
+// the highest weight.
+func searchTerms(s Screening) []searchTerm {
+	weights := make(map[string]int)
+	add := func(text string, weight int) {
+		for _, word := range Tokenize(text) {
+			weights[word] = max(weights[word], weight)
+		}
+	}
+
+	add(s.Title, searchWeightTitle)
+	add(s.Director, searchWeightPeople)
+	for _, name := range s.Cast {
+		add(name, searchWeightPeople)
+	}
+	add(s.Description, searchWeightDescription)
+
+	terms := make([]searchTerm, 0, len(weights))
+	for word, weight := range weights {
+		terms = append(terms, searchTerm{word, weight})
+	}
+	return terms
+}
+
+// SearchResult is a screening matching a search query. Higher scores are
+// better matches.
+type SearchResult struct {
+	ID    ScreeningID
+	Score int
+}
+
+// SearchIndex is an inverted index over the title, description, director and
+// cast of screenings. It is safe for concurrent use.
+type SearchIndex struct {
+	mu sync.RWMutex
+	// postings maps a word to the screenings containing it and the weight
+	// of the field it appears in.
+	postings map[string]map[ScreeningID]int
+	// words lists the words of every screening so they can be removed.
+	words map[ScreeningID][]string
+	// titles of the screenings for fuzzy matching
+	titles  map[ScreeningID]string
+	aliases *TitleAliases
+}
+
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		postings: make(map[string]map[ScreeningID]int),
+		words:    make(map[ScreeningID][]string),
+		titles:   make(map[ScreeningID]string),
+	}
+}
+
+// SetAliases sets the alternative titles used when searching.
+func (idx *SearchIndex) SetAliases(aliases *TitleAliases) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.aliases = aliases
+}
+
+// Add indexes s, replacing an earlier version of it.
+func (idx *SearchIndex) Add(s Screening) {
+	terms := searchTerms(s)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(s.ID)
+
+	words := make([]string, len(terms))
+	for i, t := range terms {
+		postings, ok := idx.postings[t.word]
+		if !ok {
+			postings = make(map[ScreeningID]int)
+			idx.postings[t.word] = postings
+		}
+		postings[s.ID] = t.weight
+		words[i] = t.word
+	}
+	idx.words[s.ID] = words
+	idx.titles[s.ID] = s.Title
+}
+
+// Remove drops the screening with the given ID from the index.
+func (idx *SearchIndex) Remove(id ScreeningID) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+}
+
+func (idx *SearchIndex) remove(id ScreeningID) {
+	for _, word := range idx.words[id] {
+		delete(idx.postings[word], id)
+		if len(idx.postings[word]) == 0 {
+			delete(idx.postings, word)
+		}
+	}
+	delete(idx.words, id)
+	delete(idx.titles, id)
+}
+
+// Search returns the screenings containing every word of query, best matches
+// first. Words match as prefixes, a whole word match scores twice as much as
+// a prefix match. Screenings whose title or one of its aliases is similar to
+// the query match as well, despite typos, scoring by their similarity.
+// Results with equal scores are in no particular order. An empty query
+// returns nothing.
+func (idx *SearchIndex) Search(query string) []SearchResult {
+	words := Tokenize(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var scores map[ScreeningID]int
+	for _, w := range words {
+		// best score of w per screening
+		wordScores := make(map[ScreeningID]int)
+		for word, postings := range idx.postings {
+			if !strings.HasPrefix(word, w) {
+				continue
+			}
+			for id, weight := range postings {
+				score := weight
+				if word == w {
+					score *= 2
+				}
+				wordScores[id] = max(wordScores[id], score)
+			}
+		}
+
+		if scores == nil {
+			scores = wordScores
+			continue
+		}
+		for id, score := range scores {
+			if wordScore, ok := wordScores[id]; ok {
+				scores[id] = score + wordScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	// A title matching perfectly scores like every word being found in the
+	// title.
+	fullScore := float64(2 * searchWeightTitle * len(words))
+	similarities := make(map[string]float64)
+	for id, title := range idx.titles {
+		similarity, ok := similarities[title]
+		if !ok {
+			similarity = idx.aliases.TitleSimilarity(query, title)
+			similarities[title] = similarity
+		}
+		if similarity >= TitleMatchThreshold {
+			scores[id] = max(scores[id], int(similarity*fullScore))
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, SearchResult{ID: id, Score: score})
+	}
+	slices.SortFunc(results, func(a, b SearchResult) int {
+		return b.Score - a.Score
+	})
+
+	return results
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Pedro Almodóvar", []string{"pedro", "almodovar"}},
+		{"Die Blechtrommel (1979)", []string{"die", "blechtrommel", "1979"}},
+		{"Straße der Sehnsucht", []string{"strasse", "der", "sehnsucht"}},
+		{"Über-Ich & Du", []string{"uber", "ich", "du"}},
+		{"ŁÓDŹ", []string{"lodz"}},
+		{"  ", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func searchFixtures() []Screening {
+	return []Screening{
+		{ID: "metropolis", Title: "Metropolis", Director: "Fritz Lang", Description: "Stummfilm mit Live-Musik."},
+		{ID: "m", Title: "M – Eine Stadt sucht einen Mörder", Director: "Fritz Lang", Cast: []string{"Peter Lorre"}},
+		{ID: "volver", Title: "Volver", Director: "Pedro Almodóvar", Cast: []string{"Penélope Cruz"}},
+		{ID: "doc", Title: "Kino in Berlin", Description: "Ein Porträt über Fritz Lang und Metropolis."},
+	}
+}
+
+func TestSearchIndex_Search(t *testing.T) {
+	idx := NewSearchIndex()
+	for _, s := range searchFixtures() {
+		idx.Add(s)
+	}
+
+	tests := []struct {
+		query string
+		want  []ScreeningID
+	}{
+		{"metropolis", []ScreeningID{"metropolis", "doc"}},
+		{"almodovar", []ScreeningID{"volver"}},
+		{"PENELOPE", []ScreeningID{"volver"}},
+		{"mörder", []ScreeningID{"m"}},
+		{"morder lorre", []ScreeningID{"m"}},
+		{"metro", []ScreeningID{"metropolis", "doc"}},
+		{"fritz metropolis", []ScreeningID{"metropolis", "doc"}},
+		{"cruz lang", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		var got []ScreeningID
+		for _, r := range idx.Search(tt.query) {
+			got = append(got, r.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchIndex_Ranking(t *testing.T) {
+	idx := NewSearchIndex()
+	for _, s := range searchFixtures() {
+		idx.Add(s)
+	}
+
+	// a whole word beats a prefix, people beat the description
+	results := idx.Search("lang")
+	if len(results) != 3 || results[2].ID != "doc" {
+		t.Fatalf("Search(lang) = %v, want doc last", results)
+	}
+	if results[0].Score != results[1].Score {
+		t.Errorf("directors scored %d and %d, want equal", results[0].Score, results[1].Score)
+	}
+}
+
+func TestSearchIndex_Add(t *testing.T) {
+	idx := NewSearchIndex()
+	idx.Add(Screening{ID: "a", Title: "Alien", Director: "Ridley Scott"})
+	idx.Add(Screening{ID: "a", Title: "Aliens", Director: "James Cameron"})
+
+	if got := idx.Search("ridley"); len(got) != 0 {
+		t.Errorf("Search(ridley) = %v, want none after update", got)
+	}
+	if got := idx.Search("cameron"); len(got) != 1 {
+		t.Errorf("Search(cameron) = %v, want a single match", got)
+	}
+
+	idx.Remove("a")
+	if got := idx.Search("aliens"); len(got) != 0 {
+		t.Errorf("Search(aliens) after Remove = %v, want none", got)
+	}
+}
+
+func TestSearchFilter(t *testing.T) {
+	var got []ScreeningID
+	for _, s := range searchFixtures() {
+		if SearchFilter("lang METRO")(s) {
+			got = append(got, s.ID)
+		}
+	}
+
+	want := []ScreeningID{"metropolis", "doc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchFilter matched %q, want %q", got, want)
+	}
+}
@@ -2,5 +2,11 @@ package domain
 
 type Storage interface {
 	Upsert(screenings Screening) error
-	Fetch(filter ...Filter) ([]Screening, error)
+	// Fetch returns the page of the screenings matching all filters and
+	// the number of matching screenings.
+	Fetch(page Page, filter ...Filter) (screenings []Screening, total int, err error)
+	// Search returns the page of the screenings matching query and all
+	// filters, best matches first unless page has an order, and the number
+	// of matching screenings.
+	Search(query string, page Page, filter ...Filter) (screenings []Screening, total int, err error)
 }
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+)
+
+// Tag is an attribute of a screening like its projection format or the kind
+// of event.
+type Tag string
+
+const (
+	Tag35mm       Tag = "35mm"
+	Tag70mm       Tag = "70mm"
+	Tag3D         Tag = "3d"
+	TagIMAX       Tag = "imax"
+	TagDolbyAtmos Tag = "dolby-atmos"
+	TagPreview    Tag = "preview"
+	TagPremiere   Tag = "premiere"
+	// TagGuests marks screenings with guests, Q&As or talks.
+	TagGuests   Tag = "guests"
+	TagShorts   Tag = "shorts"
+	TagKids     Tag = "kids"
+	TagFestival Tag = "festival"
+)
+
+// AllTags lists all tags in display order.
+var AllTags = []Tag{
+	Tag35mm, Tag70mm, Tag3D, TagIMAX, TagDolbyAtmos,
+	TagPreview, TagPremiere, TagGuests, TagShorts, TagKids, TagFestival,
+}
+
+var tagLabels = map[Tag]string{
+	Tag35mm:       "35mm",
+	Tag70mm:       "70mm",
+	Tag3D:         "3D",
+	TagIMAX:       "IMAX",
+	TagDolbyAtmos: "Dolby Atmos",
+	TagPreview:    "Preview",
+	TagPremiere:   "Premiere",
+	TagGuests:     "With guests",
+	TagShorts:     "Short films",
+	TagKids:       "Kids",
+	TagFestival:   "Festival",
+}
+
+// ParseTag returns the tag with the given name, ok is false for unknown
+// names.
+func ParseTag(name string) (tag Tag, ok bool) {
+	tag = Tag(name)
+	_, ok = tagLabels[tag]
+	return tag, ok
+}
+
+// Label returns the human readable name of the tag.
+func (t Tag) Label() string {
+	if label, ok := tagLabels[t]; ok {
+		return label
+	}
+	return string(t)
+}
+
+// Tags is a set of tags in the order of AllTags.
+type Tags []Tag
+
+// NewTags returns the set of the given tags.
+func NewTags(tags ...Tag) Tags {
+	var set Tags
+	for _, t := range AllTags {
+		if slices.Contains(tags, t) {
+			set = append(set, t)
+		}
+	}
+	return set
+}
+
+// Has reports whether the set contains t.
+func (ts Tags) Has(t Tag) bool {
+	return slices.Contains(ts, t)
+}
+
+// With returns the union of both sets.
+func (ts Tags) With(other ...Tag) Tags {
+	return NewTags(append(slices.Clone(ts), other...)...)
+}
+
+// tagPatterns detect tags in German and English programme texts.
+var tagPatterns = []struct {
+	tag Tag
+	re  *regexp.Regexp
+}{
+	{Tag35mm, regexp.MustCompile(`(?i)\b35\s?mm\b`)},
+	{Tag70mm, regexp.MustCompile(`(?i)\b70\s?mm\b`)},
+	{Tag3D, regexp.MustCompile(`(?i)\b3-?D\b`)},
+	{TagIMAX, regexp.MustCompile(`(?i)\bimax\b`)},
+	{TagDolbyAtmos, regexp.MustCompile(`(?i)\bdolby[\s-]+atmos\b`)},
+	{TagPreview, regexp.MustCompile(`(?i)\bpreview\b|vorpremiere|\bsneak\b`)},
+	// "Vorpremiere" is a preview and "Buchpremiere" no film premiere, so the
+	// word must start at a boundary
+	{TagPremiere, regexp.MustCompile(`(?i)\b(?:berlin|deutschland|welt)?-?premiere\b`)},
+	{TagGuests, regexp.MustCompile(`(?i)zu gast|\bgäste?\b|\bguests?\b|\bq\s*&\s*a\b|filmgespräch|publikumsgespräch|in anwesenheit`)},
+	{TagShorts, regexp.MustCompile(`(?i)kurzfilm|\bshort films?\b|\bshorts\b`)},
+	{TagKids, regexp.MustCompile(`(?i)kinderkino|kinderfilm|für kinder|\bkids\b|familienkino|familienfilm`)},
+	{TagFestival, regexp.MustCompile(`(?i)festival`)},
+}
+
+// DetectTags returns the tags mentioned in texts like titles, labels or
+// format names.
+func DetectTags(texts ...string) Tags {
+	var tags []Tag
+	for _, p := range tagPatterns {
+		for _, text := range texts {
+			if p.re.MatchString(text) {
+				tags = append(tags, p.tag)
+				break
+			}
+		}
+	}
+	return NewTags(tags...)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectTags(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  Tags
+	}{
+		{[]string{"Lawrence of Arabia in 70mm"}, Tags{Tag70mm}},
+		{[]string{"Metropolis", "35 mm"}, Tags{Tag35mm}},
+		{[]string{"Avatar 3D", "IMAX", "Dolby Atmos"}, Tags{Tag3D, TagIMAX, TagDolbyAtmos}},
+		{[]string{"Vorpremiere: Das Lehrerzimmer"}, Tags{TagPreview}},
+		{[]string{"Berlin-Premiere mit anschließendem Q&A"}, Tags{TagPremiere, TagGuests}},
+		{[]string{"Buchpremiere mit Shelly Kupferberg"}, nil},
+		{[]string{"Regisseurin zu Gast"}, Tags{TagGuests}},
+		{[]string{"Kurzfilmprogramm #3"}, Tags{TagShorts}},
+		{[]string{"Kinderkino: Pippi Langstrumpf"}, Tags{TagKids}},
+		{[]string{"Berlinale Festival Special"}, Tags{TagFestival}},
+		{[]string{"3Dimensional", "Imaxx"}, nil},
+	}
+
+	for _, tt := range tests {
+		if got := DetectTags(tt.texts...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DetectTags(%q) = %v, want %v", tt.texts, got, tt.want)
+		}
+	}
+}
+
+func TestTags_With(t *testing.T) {
+	got := Tags{TagKids, TagFestival}.With(Tag35mm, TagKids)
+	want := Tags{Tag35mm, TagKids, TagFestival}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("With() = %v, want %v", got, want)
+	}
+}
+
+func TestTagFilter(t *testing.T) {
+	s := Screening{Tags: NewTags(Tag35mm, TagGuests)}
+
+	tests := []struct {
+		tags []Tag
+		want bool
+	}{
+		{nil, true},
+		{[]Tag{Tag35mm}, true},
+		{[]Tag{Tag35mm, TagGuests}, true},
+		{[]Tag{Tag35mm, TagKids}, false},
+	}
+
+	for _, tt := range tests {
+		if got := TagFilter(tt.tags...)(s); got != tt.want {
+			t.Errorf("TagFilter(%v) = %v, want %v", tt.tags, got, tt.want)
+		}
+	}
+}
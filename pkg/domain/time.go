@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Berlin is the time zone of all cinemas.
+var Berlin = mustLoadLocation("Europe/Berlin")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(fmt.Sprintf("loading location %q: %v", name, err))
+	}
+	return loc
+}
+
+// ParseClock parses a time of day like "19:30" into the offset since
+// midnight.
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// clock returns the time of day of t in Berlin as offset since midnight.
+func clock(t time.Time) time.Duration {
+	h, m, s := t.In(Berlin).Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+}
+
+// CinemaDays tells which cinema day a screening belongs to. Screenings
+// starting before Rollover belong to the day before, so with a rollover of
+// 04:00 a late show at 00:30 is listed with the evening it belongs to. The
+// zero value begins cinema days at midnight.
+type CinemaDays struct {
+	// Rollover is the time of day in Berlin, as offset since midnight, at
+	// which a new cinema day begins.
+	Rollover time.Duration
+}
+
+// Day returns the cinema day t belongs to as midnight in Berlin. All
+// grouping and filtering by day uses cinema days.
+func (c CinemaDays) Day(t time.Time) time.Time {
+	local := t.In(Berlin)
+	year, month, d := local.Date()
+	if clock(local) < c.Rollover {
+		d--
+	}
+	return time.Date(year, month, d, 0, 0, 0, 0, Berlin)
+}
+
+// Date returns midnight in Berlin of the calendar date of t in its own
+// location. Use it for dates parsed from user input like "2025-03-08".
+func Date(t time.Time) time.Time {
+	year, month, d := t.Date()
+	return time.Date(year, month, d, 0, 0, 0, 0, Berlin)
+}
+
+// WeekStart returns midnight in Berlin of the Monday in the week of the
+// calendar date of t, like Date. Pass CinemaDays.Day(t) for the week a
+// screening belongs to.
+func WeekStart(t time.Time) time.Time {
+	d := Date(t)
+	return d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+}
+
+// sinceDayStart converts a time of day to the offset since the start of the
+// cinema day, so that times after midnight sort after the evening.
+func (c CinemaDays) sinceDayStart(clock time.Duration) time.Duration {
+	return ((clock-c.Rollover)%(24*time.Hour) + 24*time.Hour) % (24 * time.Hour)
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sonntag": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "montag": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "dienstag": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "mittwoch": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "donnerstag": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "freitag": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "samstag": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseWeekday parses English or German weekday names like "tuesday",
+// "tue" or "Dienstag".
+func ParseWeekday(s string) (time.Weekday, error) {
+	day, ok := weekdays[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("unknown weekday %q", s)
+	}
+	return day, nil
+}
+
+// ParseRuntime parses a runtime given in minutes like "100" or as a duration
+// like "1h40m".
+func ParseRuntime(s string) (time.Duration, error) {
+	if minutes, err := strconv.Atoi(s); err == nil && minutes > 0 {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid runtime %q, want minutes like 100 or a duration like 1h40m", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCinemaDays_Day(t *testing.T) {
+	date := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 0, 0, 0, 0, Berlin)
+	}
+
+	tests := []struct {
+		name     string
+		rollover time.Duration
+		start    time.Time
+		want     time.Time
+	}{
+		{"evening", 4 * time.Hour, time.Date(2025, 3, 7, 20, 0, 0, 0, Berlin), date(3, 7)},
+		{"after midnight", 4 * time.Hour, time.Date(2025, 3, 8, 0, 30, 0, 0, Berlin), date(3, 7)},
+		{"at rollover", 4 * time.Hour, time.Date(2025, 3, 8, 4, 0, 0, 0, Berlin), date(3, 8)},
+		{"midnight without rollover", 0, time.Date(2025, 3, 8, 0, 30, 0, 0, Berlin), date(3, 8)},
+		{"UTC without rollover", 0, time.Date(2025, 3, 7, 23, 30, 0, 0, time.UTC), date(3, 8)},
+		{"UTC with rollover", 4 * time.Hour, time.Date(2025, 3, 7, 23, 30, 0, 0, time.UTC), date(3, 7)},
+		{"first of month", 4 * time.Hour, time.Date(2025, 3, 1, 1, 0, 0, 0, Berlin), date(2, 28)},
+		// Summer time starts on 30 March 2025, 02:00 CET becomes 03:00 CEST.
+		{"before spring forward", 4 * time.Hour, time.Date(2025, 3, 30, 1, 30, 0, 0, Berlin), date(3, 29)},
+		{"after spring forward", 4 * time.Hour, time.Date(2025, 3, 30, 1, 30, 0, 0, time.UTC), date(3, 29)},
+		{"spring forward at rollover", 4 * time.Hour, time.Date(2025, 3, 30, 2, 0, 0, 0, time.UTC), date(3, 30)},
+		{"spring forward without rollover", 0, time.Date(2025, 3, 29, 23, 30, 0, 0, time.UTC), date(3, 30)},
+		// Summer time ends on 26 October 2025, 03:00 CEST becomes 02:00 CET,
+		// so 02:30 happens twice.
+		{"first 02:30 in autumn", 4 * time.Hour, time.Date(2025, 10, 26, 0, 30, 0, 0, time.UTC), date(10, 25)},
+		{"second 02:30 in autumn", 4 * time.Hour, time.Date(2025, 10, 26, 1, 30, 0, 0, time.UTC), date(10, 25)},
+		{"fall back at rollover", 4 * time.Hour, time.Date(2025, 10, 26, 3, 0, 0, 0, time.UTC), date(10, 26)},
+		{"fall back without rollover", 0, time.Date(2025, 10, 25, 22, 30, 0, 0, time.UTC), date(10, 26)},
+	}
+
+	for _, tt := range tests {
+		days := CinemaDays{Rollover: tt.rollover}
+		if got := days.Day(tt.start); !got.Equal(tt.want) {
+			t.Errorf("%s: Day(%v) = %v, want %v", tt.name, tt.start, got, tt.want)
+		}
+	}
+}
+
+func TestCinemaDays_Filters(t *testing.T) {
+	days := CinemaDays{Rollover: 4 * time.Hour}
+
+	// Saturday 00:30, the late show of Friday
+	late := Screening{Start: time.Date(2025, 3, 8, 0, 30, 0, 0, Berlin)}
+	c := func(s string) time.Duration {
+		d, err := ParseClock(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"date of the evening", days.DateFilter(time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)), true},
+		{"calendar date", days.DateFilter(time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)), false},
+		{"range", days.DateRangeFilter(time.Date(2025, 3, 6, 0, 0, 0, 0, Berlin), time.Date(2025, 3, 7, 0, 0, 0, 0, Berlin)), true},
+		{"weekday", days.WeekdayFilter(time.Friday), true},
+		{"after 22:00", days.StartsAfterFilter(c("22:00")), true},
+		{"before 23:00", days.StartsBeforeFilter(c("23:00")), false},
+		{"between 22:00 and 02:00", days.StartsBetweenFilter(c("22:00"), c("02:00")), true},
+		{"between 02:00 and 22:00", days.StartsBetweenFilter(c("02:00"), c("22:00")), false},
+		{"today at 01:00", days.PeriodFilter(PeriodToday, time.Date(2025, 3, 8, 1, 0, 0, 0, Berlin)), true},
+		{"tomorrow on Friday", days.PeriodFilter(PeriodTomorrow, time.Date(2025, 3, 7, 12, 0, 0, 0, Berlin)), false},
+		{"calendar date without rollover", CinemaDays{}.DateFilter(time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)), true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter(late); got != tt.want {
+			t.Errorf("%s: filter(late show) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2025, 3, 24, 0, 0, 0, 0, Berlin)
+	tests := []struct {
+		name string
+		in   time.Time
+	}{
+		{"monday", time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC)},
+		{"sunday", time.Date(2025, 3, 30, 23, 0, 0, 0, Berlin)},
+		{"sunday in UTC", time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)},
+		{"across DST", time.Date(2025, 3, 31, 12, 0, 0, 0, Berlin).AddDate(0, 0, -1)},
+	}
+
+	for _, tt := range tests {
+		if got := WeekStart(tt.in); !got.Equal(monday) {
+			t.Errorf("%s: WeekStart(%v) = %v, want %v", tt.name, tt.in, got, monday)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Package httpcache stores HTTP responses on disk so that restarts do not
+// download every programme page again.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const entrySuffix = ".json"
+
+// Entry is a cached response.
+type Entry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+	// ExpiresAt is when the entry becomes stale and has to be revalidated.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Fresh reports whether the entry can be served without asking the origin.
+func (e Entry) Fresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// Validators returns the ETag and Last-Modified of the entry.
+func (e Entry) Validators() (etag, lastModified string) {
+	return e.Header.Get("ETag"), e.Header.Get("Last-Modified")
+}
+
+// Response builds a response for req from the entry.
+func (e Entry) Response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(time.Since(e.StoredAt).Seconds())))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// Store is a directory of cached responses, one file per URL.
+type Store struct {
+	dir string
+	// minTTL forces responses to be cached for at least this long, even if
+	// the origin forbids caching.
+	minTTL time.Duration
+}
+
+func New(dir string, minTTL time.Duration) *Store {
+	return &Store{
+		dir:    dir,
+		minTTL: minTTL,
+	}
+}
+
+// DefaultDir returns the cache directory in the user's cache directory.
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "kino-berlin", "http")
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+func (s *Store) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+entrySuffix)
+}
+
+// Get returns the entry of url. ok is false if there is none.
+func (s *Store) Get(url string) (entry Entry, ok bool, err error) {
+	data, err := os.ReadFile(s.path(url))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("reading cache entry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, false, fmt.Errorf("decoding cache entry: %w", err)
+	}
+
+	return entry, true, nil
+}
+
+// Put stores the response of url if it may be cached. now is the time the
+// response was received.
+func (s *Store) Put(url string, res *http.Response, body []byte, now time.Time) error {
+	expiresAt, ok := s.expiry(res.Header, now)
+	if !ok {
+		return nil
+	}
+
+	return s.write(Entry{
+		URL:        url,
+		StatusCode: res.StatusCode,
+		Header:     res.Header.Clone(),
+		Body:       body,
+		StoredAt:   now,
+		ExpiresAt:  expiresAt,
+	})
+}
+
+// Refresh extends the freshness of an entry after the origin confirmed with
+// 304 Not Modified that it is still valid.
+func (s *Store) Refresh(entry Entry, header http.Header, now time.Time) error {
+	// the 304 may carry updated caching headers
+	for _, key := range []string{"Cache-Control", "Expires", "Date", "ETag", "Last-Modified"} {
+		if v := header.Get(key); v != "" {
+			entry.Header.Set(key, v)
+		}
+	}
+
+	expiresAt, ok := s.expiry(entry.Header, now)
+	if !ok {
+		return s.Delete(entry.URL)
+	}
+
+	entry.StoredAt = now
+	entry.ExpiresAt = expiresAt
+
+	return s.write(entry)
+}
+
+func (s *Store) write(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	// write to a temporary file first so that readers never see partial
+	// entries
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(entry.URL)); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// expiry computes when a response with header, received at now, becomes
+// stale. ok is false if the response must not be stored.
+func (s *Store) expiry(header http.Header, now time.Time) (expiresAt time.Time, ok bool) {
+	directives := parseCacheControl(header.Get("Cache-Control"))
+
+	_, noStore := directives["no-store"]
+	_, noCache := directives["no-cache"]
+	_, private := directives["private"]
+
+	expiresAt = now
+	switch {
+	case noStore || noCache || private:
+		// stale immediately, only minTTL can keep the entry fresh
+	case directives["max-age"] != "":
+		if seconds, err := strconv.Atoi(directives["max-age"]); err == nil {
+			expiresAt = now.Add(time.Duration(seconds) * time.Second)
+		}
+	case header.Get("Expires") != "":
+		if t, err := http.ParseTime(header.Get("Expires")); err == nil {
+			expiresAt = t
+		}
+	}
+
+	if minExpiry := now.Add(s.minTTL); minExpiry.After(expiresAt) {
+		expiresAt = minExpiry
+	}
+
+	if (noStore || private) && !expiresAt.After(now) {
+		return time.Time{}, false
+	}
+
+	return expiresAt, true
+}
+
+func parseCacheControl(value string) map[string]string {
+	directives := make(map[string]string)
+	for part := range strings.SplitSeq(value, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if key == "" {
+			continue
+		}
+		directives[strings.ToLower(key)] = strings.Trim(val, `"`)
+	}
+	return directives
+}
+
+// Delete removes the entry of url.
+func (s *Store) Delete(url string) error {
+	err := os.Remove(s.path(url))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("deleting cache entry: %w", err)
+	}
+	return nil
+}
+
+// Entries returns all entries sorted by URL.
+func (s *Store) Entries() ([]Entry, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*"+entrySuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading cache entry: %w", err)
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("decoding cache entry %q: %w", file, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].URL < entries[j].URL
+	})
+
+	return entries, nil
+}
+
+// Clear removes all entries and returns how many were removed.
+func (s *Store) Clear() (int, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*"+entrySuffix))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return 0, fmt.Errorf("deleting cache entry: %w", err)
+		}
+	}
+
+	return len(files), nil
+}
@@ -0,0 +1,82 @@
+package httpcache
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStore_Expiry(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		minTTL   time.Duration
+		want     time.Duration
+		wantKeep bool
+	}{
+		{"max-age", http.Header{"Cache-Control": {"public, max-age=3600"}}, 0, time.Hour, true},
+		{"expires", http.Header{"Expires": {now.Add(2 * time.Hour).Format(http.TimeFormat)}}, 0, 2 * time.Hour, true},
+		{"max-age over expires", http.Header{"Cache-Control": {"max-age=60"}, "Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}, 0, time.Minute, true},
+		{"no headers", http.Header{}, 0, 0, true},
+		{"min ttl", http.Header{"Cache-Control": {"max-age=60"}}, 10 * time.Minute, 10 * time.Minute, true},
+		{"no-cache", http.Header{"Cache-Control": {"no-cache"}}, 0, 0, true},
+		{"no-store", http.Header{"Cache-Control": {"no-store"}}, 0, 0, false},
+		{"no-store with min ttl", http.Header{"Cache-Control": {"no-store"}}, time.Minute, time.Minute, true},
+		{"private", http.Header{"Cache-Control": {"private, max-age=3600"}}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(t.TempDir(), tt.minTTL)
+
+			got, ok := s.expiry(tt.header, now)
+			if ok != tt.wantKeep {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantKeep)
+			}
+			if ok && got.Sub(now) != tt.want {
+				t.Errorf("expires after %v, want %v", got.Sub(now), tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_PutGetClear(t *testing.T) {
+	s := New(t.TempDir(), 0)
+	now := time.Now()
+	url := "https://example.com/programm"
+
+	res := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Cache-Control": {"max-age=60"},
+			"Etag":          {`"v1"`},
+		},
+	}
+	if err := s.Put(url, res, []byte("programme"), now); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	entry, ok, err := s.Get(url)
+	if err != nil || !ok {
+		t.Fatalf("Get() = _, %v, %v, want entry", ok, err)
+	}
+	if string(entry.Body) != "programme" {
+		t.Errorf("Body = %q, want %q", entry.Body, "programme")
+	}
+	if !entry.Fresh(now.Add(30*time.Second)) || entry.Fresh(now.Add(2*time.Minute)) {
+		t.Errorf("ExpiresAt = %v, want %v", entry.ExpiresAt, now.Add(time.Minute))
+	}
+	if etag, _ := entry.Validators(); etag != `"v1"` {
+		t.Errorf("ETag = %q, want %q", etag, `"v1"`)
+	}
+
+	n, err := s.Clear()
+	if err != nil || n != 1 {
+		t.Fatalf("Clear() = %d, %v, want 1", n, err)
+	}
+	if _, ok, _ := s.Get(url); ok {
+		t.Error("entry still cached after Clear()")
+	}
+}
@@ -0,0 +1,259 @@
+// Package httpclient provides the HTTP client shared by all providers. It
+// identifies itself with a configurable User-Agent, rate limits requests per
+// host, honours robots.txt and supports conditional requests.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpcache"
+)
+
+const DefaultUserAgent = "kino-berlin/1.0 (+https://github.com/PhilippReinke/kino-berlin)"
+
+var (
+	// ErrDisallowed is returned for requests forbidden by robots.txt.
+	ErrDisallowed = errors.New("disallowed by robots.txt")
+	// ErrNotModified is returned for conditional requests whose resource has
+	// not changed since the last response.
+	ErrNotModified = errors.New("not modified")
+)
+
+// Config configures a Client. Zero values fall back to the defaults.
+type Config struct {
+	UserAgent string
+	// Timeout limits a whole request including reading the body.
+	Timeout time.Duration
+	// MinInterval is the minimum delay between two requests to the same
+	// host. A larger Crawl-delay from robots.txt takes precedence.
+	MinInterval time.Duration
+	// IgnoreRobots disables robots.txt checks.
+	IgnoreRobots bool
+	// Cache stores responses on disk. Optional.
+	Cache *httpcache.Store
+}
+
+func (c Config) withDefaults() Config {
+	if c.UserAgent == "" {
+		c.UserAgent = DefaultUserAgent
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 30 * time.Second
+	}
+	if c.MinInterval == 0 {
+		c.MinInterval = time.Second
+	}
+	return c
+}
+
+type Client struct {
+	config    Config
+	transport *Transport
+	client    *http.Client
+}
+
+func New(config Config) *Client {
+	config = config.withDefaults()
+
+	transport := &Transport{
+		config:     config,
+		base:       http.DefaultTransport,
+		limiters:   make(map[string]*hostLimiter),
+		robots:     make(map[string]*robotsEntry),
+		validators: make(map[string]validator),
+	}
+
+	return &Client{
+		config:    config,
+		transport: transport,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   config.Timeout,
+		},
+	}
+}
+
+// HTTP returns a client for net/http based providers.
+func (c *Client) HTTP() *http.Client {
+	return c.client
+}
+
+// Transport returns the round tripper for scraping frameworks that manage
+// their own http.Client, like colly.
+func (c *Client) Transport() http.RoundTripper {
+	return c.transport
+}
+
+// Config returns the effective configuration.
+func (c *Client) Config() Config {
+	return c.config
+}
+
+type conditionalKey struct{}
+
+// WithConditional marks requests made with ctx as conditional: they carry the
+// validators (ETag, Last-Modified) of the previous response for the same URL
+// and fail with ErrNotModified if the server answers 304. Callers should only
+// use it if they kept the result of the previous response.
+func WithConditional(ctx context.Context) context.Context {
+	return context.WithValue(ctx, conditionalKey{}, true)
+}
+
+func isConditional(ctx context.Context) bool {
+	conditional, _ := ctx.Value(conditionalKey{}).(bool)
+	return conditional
+}
+
+// Transport implements the polite HTTP behaviour of Client.
+type Transport struct {
+	config Config
+	base   http.RoundTripper
+
+	mu         sync.Mutex
+	limiters   map[string]*hostLimiter
+	robots     map[string]*robotsEntry
+	validators map[string]validator
+}
+
+type validator struct {
+	etag         string
+	lastModified string
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the request must not be modified, see http.RoundTripper
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.config.UserAgent)
+
+	key := req.URL.String()
+	isGet := req.Method == http.MethodGet
+	conditional := isGet && isConditional(req.Context())
+
+	var (
+		cached    httpcache.Entry
+		hasCached bool
+	)
+	if isGet && t.config.Cache != nil {
+		var err error
+		cached, hasCached, err = t.config.Cache.Get(key)
+		if err != nil {
+			log.Printf("Ignoring HTTP cache for %s: %v", key, err)
+		}
+		if hasCached && cached.Fresh(time.Now()) {
+			return t.fromCache(req, cached, conditional)
+		}
+	}
+
+	if !t.config.IgnoreRobots {
+		allowed, err := t.allowed(req)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%s: %w", req.URL, ErrDisallowed)
+		}
+	}
+
+	// conditional callers are asked about the version they have seen last,
+	// otherwise a stale cache entry is revalidated
+	var sent validator
+	if conditional {
+		t.mu.Lock()
+		sent = t.validators[key]
+		t.mu.Unlock()
+	} else if hasCached {
+		sent.etag, sent.lastModified = cached.Validators()
+	}
+	if sent.etag != "" {
+		req.Header.Set("If-None-Match", sent.etag)
+	}
+	if sent.lastModified != "" {
+		req.Header.Set("If-Modified-Since", sent.lastModified)
+	}
+
+	if err := t.wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && sent != (validator{}):
+		res.Body.Close()
+
+		etag, lastModified := cached.Validators()
+		cacheMatches := hasCached && sent == validator{etag: etag, lastModified: lastModified}
+		if cacheMatches {
+			if err := t.config.Cache.Refresh(cached, res.Header, now); err != nil {
+				log.Printf("Refreshing HTTP cache for %s failed: %v", key, err)
+			}
+		}
+
+		if conditional {
+			return nil, fmt.Errorf("%s: %w", req.URL, ErrNotModified)
+		}
+		return t.fromCache(req, cached, false)
+	case res.StatusCode == http.StatusOK && isGet:
+		t.remember(key, validator{
+			etag:         res.Header.Get("ETag"),
+			lastModified: res.Header.Get("Last-Modified"),
+		})
+
+		if t.config.Cache != nil {
+			body, err := io.ReadAll(res.Body)
+			res.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("reading body: %w", err)
+			}
+			if err := t.config.Cache.Put(key, res, body, now); err != nil {
+				log.Printf("Writing HTTP cache for %s failed: %v", key, err)
+			}
+			res.Body = io.NopCloser(bytes.NewReader(body))
+		}
+	}
+
+	return res, nil
+}
+
+// fromCache answers req with a cache entry. Conditional callers that have
+// already seen this version of the entry get ErrNotModified.
+func (t *Transport) fromCache(req *http.Request, entry httpcache.Entry, conditional bool) (*http.Response, error) {
+	etag, lastModified := entry.Validators()
+	v := validator{etag: etag, lastModified: lastModified}
+
+	t.mu.Lock()
+	seen := t.validators[req.URL.String()] == v && v != (validator{})
+	t.mu.Unlock()
+
+	if conditional && seen {
+		return nil, fmt.Errorf("%s: %w", req.URL, ErrNotModified)
+	}
+
+	t.remember(req.URL.String(), v)
+
+	return entry.Response(req), nil
+}
+
+// remember records the validators of the last response delivered for key.
+func (t *Transport) remember(key string, v validator) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if v == (validator{}) {
+		delete(t.validators, key)
+		return
+	}
+	t.validators[key] = v
+}
@@ -0,0 +1,168 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpcache"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var programmeRequests atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+	})
+	mux.HandleFunc("GET /programm", func(w http.ResponseWriter, r *http.Request) {
+		programmeRequests.Add(1)
+		if r.Header.Get("User-Agent") != "test-agent" {
+			t.Errorf("User-Agent = %q, want %q", r.Header.Get("User-Agent"), "test-agent")
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, "programme")
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv, &programmeRequests
+}
+
+func get(ctx context.Context, c *Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.HTTP().Do(req)
+}
+
+func TestClient_Conditional(t *testing.T) {
+	srv, requests := newTestServer(t)
+	c := New(Config{UserAgent: "test-agent", MinInterval: time.Millisecond})
+
+	res, err := get(context.Background(), c, srv.URL+"/programm")
+	if err != nil {
+		t.Fatalf("first request error = %v", err)
+	}
+	res.Body.Close()
+
+	if _, err := get(WithConditional(context.Background()), c, srv.URL+"/programm"); !errors.Is(err, ErrNotModified) {
+		t.Fatalf("conditional request error = %v, want %v", err, ErrNotModified)
+	}
+
+	// unconditional requests always get the body
+	res, err = get(context.Background(), c, srv.URL+"/programm")
+	if err != nil {
+		t.Fatalf("unconditional request error = %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d", res.StatusCode, http.StatusOK)
+	}
+
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server got %d requests, want 3", got)
+	}
+}
+
+func TestClient_Robots(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	c := New(Config{UserAgent: "test-agent", MinInterval: time.Millisecond})
+	if _, err := get(context.Background(), c, srv.URL+"/private/page"); !errors.Is(err, ErrDisallowed) {
+		t.Errorf("error = %v, want %v", err, ErrDisallowed)
+	}
+
+	c = New(Config{UserAgent: "test-agent", MinInterval: time.Millisecond, IgnoreRobots: true})
+	res, err := get(context.Background(), c, srv.URL+"/private/page")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	res.Body.Close()
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	srv, _ := newTestServer(t)
+	c := New(Config{UserAgent: "test-agent", MinInterval: 50 * time.Millisecond})
+
+	start := time.Now()
+	// the first request also fetches robots.txt, so there are four
+	// requests spaced by the interval
+	for range 3 {
+		res, err := get(context.Background(), c, srv.URL+"/programm")
+		if err != nil {
+			t.Fatalf("error = %v", err)
+		}
+		res.Body.Close()
+	}
+
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("3 requests plus robots.txt took %v, want at least 150ms", elapsed)
+	}
+}
+
+func TestClient_Cache(t *testing.T) {
+	srv, requests := newTestServer(t)
+	cache := httpcache.New(t.TempDir(), time.Hour)
+
+	for i := range 2 {
+		// every iteration simulates a restart with a new client
+		c := New(Config{UserAgent: "test-agent", MinInterval: time.Millisecond, Cache: cache})
+
+		res, err := get(context.Background(), c, srv.URL+"/programm")
+		if err != nil {
+			t.Fatalf("request %d error = %v", i, err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if string(body) != "programme" {
+			t.Errorf("request %d body = %q, want %q", i, body, "programme")
+		}
+
+		// the cached version has already been delivered by this client
+		if _, err := get(WithConditional(context.Background()), c, srv.URL+"/programm"); !errors.Is(err, ErrNotModified) {
+			t.Errorf("conditional request %d error = %v, want %v", i, err, ErrNotModified)
+		}
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+func TestClient_CacheRevalidate(t *testing.T) {
+	srv, requests := newTestServer(t)
+	// without a minimum TTL the response is stale immediately
+	cache := httpcache.New(t.TempDir(), 0)
+
+	for i := range 2 {
+		c := New(Config{UserAgent: "test-agent", MinInterval: time.Millisecond, Cache: cache})
+
+		res, err := get(context.Background(), c, srv.URL+"/programm")
+		if err != nil {
+			t.Fatalf("request %d error = %v", i, err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK || string(body) != "programme" {
+			t.Errorf("request %d = %d %q, want 200 %q", i, res.StatusCode, body, "programme")
+		}
+	}
+
+	// the second request is answered with 304 and served from the cache
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2", got)
+	}
+}
@@ -0,0 +1,67 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// hostLimiter spaces requests to a host at least interval apart.
+type hostLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// limiter returns the limiter of host. t.mu must be held.
+func (t *Transport) limiter(host string) *hostLimiter {
+	l, ok := t.limiters[host]
+	if !ok {
+		l = &hostLimiter{interval: t.config.MinInterval}
+		t.limiters[host] = l
+	}
+	return l
+}
+
+// wait blocks until a request to host may be sent.
+func (t *Transport) wait(ctx context.Context, host string) error {
+	t.mu.Lock()
+	l := t.limiter(host)
+	t.mu.Unlock()
+
+	return l.wait(ctx)
+}
+
+func (l *hostLimiter) setInterval(interval time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.interval = interval
+}
+
+func (l *hostLimiter) wait(ctx context.Context) error {
+	// reserve the next slot, so that concurrent callers queue up
+	l.mu.Lock()
+	now := time.Now()
+	slot := now
+	if l.next.After(now) {
+		slot = l.next
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
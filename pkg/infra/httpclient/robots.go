@@ -0,0 +1,98 @@
+package httpclient
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/temoto/robotstxt"
+)
+
+// robotsTTL is how long a fetched robots.txt is trusted.
+const robotsTTL = 24 * time.Hour
+
+type robotsEntry struct {
+	group     *robotstxt.Group
+	fetchedAt time.Time
+}
+
+// allowed reports whether robots.txt of the request's host permits req.
+func (t *Transport) allowed(req *http.Request) (bool, error) {
+	host := req.URL.Scheme + "://" + req.URL.Host
+
+	t.mu.Lock()
+	entry, ok := t.robots[host]
+	t.mu.Unlock()
+
+	if !ok || time.Since(entry.fetchedAt) > robotsTTL {
+		var err error
+		entry, err = t.fetchRobots(req, host)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if entry == nil {
+		return true, nil
+	}
+
+	return entry.group.Test(req.URL.EscapedPath()), nil
+}
+
+// fetchRobots fetches and caches robots.txt of host. It returns a nil entry
+// if robots.txt could not be loaded due to a temporary problem, in which case
+// the request is allowed and robots.txt is tried again next time.
+func (t *Transport) fetchRobots(req *http.Request, host string) (*robotsEntry, error) {
+	robotsURL, err := url.JoinPath(host, "robots.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	robotsReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	robotsReq.Header.Set("User-Agent", t.config.UserAgent)
+
+	if err := t.wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+
+	res, err := t.base.RoundTrip(robotsReq)
+	if err != nil {
+		log.Printf("Fetching %s failed, allowing request: %v", robotsURL, err)
+		return nil, nil
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 500 {
+		log.Printf("Fetching %s failed with %q, allowing request", robotsURL, res.Status)
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		log.Printf("Reading %s failed, allowing request: %v", robotsURL, err)
+		return nil, nil
+	}
+
+	data, err := robotstxt.FromStatusAndBytes(res.StatusCode, body)
+	if err != nil {
+		log.Printf("Parsing %s failed, allowing request: %v", robotsURL, err)
+		return nil, nil
+	}
+
+	entry := &robotsEntry{
+		group:     data.FindGroup(t.config.UserAgent),
+		fetchedAt: time.Now(),
+	}
+
+	t.mu.Lock()
+	t.robots[host] = entry
+	t.limiter(req.URL.Host).setInterval(max(t.config.MinInterval, entry.group.CrawlDelay))
+	t.mu.Unlock()
+
+	return entry, nil
+}
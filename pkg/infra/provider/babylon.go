@@ -1,93 +1,317 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpclient"
 	"github.com/gocolly/colly/v2"
 )
 
+// babylonMaxPages bounds the number of programme pages visited per scrape in
+// case the pagination links form a loop we do not detect.
+const babylonMaxPages = 24
+
+// babylonNextSelector matches links to the next page of the programme, e.g.
+// the following month.
+const babylonNextSelector = `a[rel="next"], link[rel="next"], .pagination-next a`
+
 type Babylon struct {
-	c       *colly.Collector
+	client  *httpclient.Client
 	baseURL string
+	// paths are the programme pages a scrape starts from. Pages linked from
+	// them as next page are visited as well.
+	paths []string
+	// pages keeps the last result per programme page, so that unchanged
+	// pages (HTTP 304) are answered without parsing them again.
+	pages *resultCache[babylonPage]
+	// details keeps the parsed detail page per URL.
+	details *resultCache[babylonDetail]
+	// detailWorkers limits how many detail pages are fetched concurrently.
+	// The client's per-host rate limit applies on top.
+	detailWorkers int
 }
 
 var _ domain.Provider = &Babylon{}
 
+func init() {
+	Register("babylon", func(opts Options) (domain.Provider, error) {
+		b := newBabylon(opts.client())
+		if opts.BaseURL != "" {
+			b.baseURL = opts.BaseURL
+		}
+		if len(opts.Sources) > 0 {
+			b.paths = opts.Sources
+		}
+		return b, nil
+	})
+}
+
 func NewBabylon() *Babylon {
+	return newBabylon(httpclient.New(httpclient.Config{}))
+}
+
+func newBabylon(client *httpclient.Client) *Babylon {
 	return &Babylon{
-		c:       colly.NewCollector(),
+		client:  client,
 		baseURL: "https://babylonberlin.eu",
+		paths:   []string{"/programm"},
+		pages:   newResultCache[babylonPage](),
+		details: newResultCache[babylonDetail](),
+
+		detailWorkers: 4,
 	}
 }
 
+// babylonPage is the result of scraping a single programme page.
+type babylonPage struct {
+	screenings []domain.Screening
+	next       []string
+}
+
 func (b Babylon) Name() string {
 	return "Kino Babylon"
 }
 
 func (b Babylon) Scrape() ([]domain.Screening, error) {
-	var screenings []domain.Screening
+	queue := make([]string, 0, len(b.paths))
+	for _, path := range b.paths {
+		queue = append(queue, b.baseURL+path)
+	}
 
-	b.c.OnHTML("#regridart-207", func(e *colly.HTMLElement) {
-		e.ForEach("li", func(n int, e *colly.HTMLElement) {
-			titles := e.ChildTexts("h3")
-			if len(titles) <= 2 {
-				return
-			}
+	var (
+		screenings []domain.Screening
+		seenPages  = make(map[string]bool)
+		seenIDs    = make(map[domain.ScreeningID]bool)
+		now        = time.Now()
+	)
+	for len(queue) > 0 && len(seenPages) < babylonMaxPages {
+		pageURL := queue[0]
+		queue = queue[1:]
+		if seenPages[pageURL] {
+			continue
+		}
+		seenPages[pageURL] = true
 
-			date, err := parseDate(e.Attr("data-date"))
-			if err != nil {
-				log.Printf("Failed to parse date: %v", err)
-				return
-			}
+		page, err := b.scrapePage(pageURL, now)
+		if err != nil {
+			return []domain.Screening{}, err
+		}
 
-			var duration time.Duration
-			runtimeTexts := e.ChildTexts(".runtime")
-			if len(runtimeTexts) > 0 {
-				var err error
-				duration, err = parseDuration(runtimeTexts[0])
-				if err != nil {
-					log.Printf("Failed to parse duration: %v", err)
-				}
+		// screenings may be listed on several pages, e.g. around the turn of
+		// the month
+		for _, s := range page.screenings {
+			if seenIDs[s.ID] {
+				continue
 			}
+			seenIDs[s.ID] = true
+			screenings = append(screenings, s)
+		}
+		queue = append(queue, page.next...)
+	}
+
+	b.addDetails(screenings, now)
+
+	return screenings, nil
+}
+
+// scrapePage scrapes a single programme page with a fresh collector. colly
+// collectors refuse to visit a URL twice and accumulate callbacks, so they
+// must not outlive a scrape.
+func (b Babylon) scrapePage(pageURL string, now time.Time) (babylonPage, error) {
+	last, _, hasLast := b.pages.get(pageURL)
 
-			link := b.baseURL + e.ChildAttr(".mix-title", "href")
-
-			title := e.ChildTexts("h3")[2]
-
-			language := "" // TODO
-
-			screeningID := domain.NewScreeningID(
-				title,
-				date,
-				b.Name(),
-				language,
-			)
-
-			screenings = append(screenings, domain.Screening{
-				ID:          screeningID,
-				Title:       title,
-				Description: "",
-				Start:       date,
-				Duration:    duration,
-				Cinema:      b.Name(),
-				Language:    language,
-				Links: domain.ScreeningLinks{
-					Details:       link,
-					ThumbnailLink: e.ChildAttr(".fancybox", "href"),
-				},
-				UpdatedAt: time.Now(),
-			})
+	c := b.newCollector()
+	if hasLast {
+		c.Context = httpclient.WithConditional(c.Context)
+	}
+
+	var page babylonPage
+	c.OnHTML("#regridart-207", func(e *colly.HTMLElement) {
+		e.ForEach("li", func(_ int, e *colly.HTMLElement) {
+			if s, ok := b.parseScreening(e, now); ok {
+				page.screenings = append(page.screenings, s)
+			}
 		})
 	})
+	c.OnHTML(babylonNextSelector, func(e *colly.HTMLElement) {
+		next := e.Request.AbsoluteURL(e.Attr("href"))
+		if next != "" && next != pageURL && sameHost(next, b.baseURL) {
+			page.next = append(page.next, next)
+		}
+	})
+
+	var httpErr error
+	c.OnError(func(r *colly.Response, err error) {
+		if r.StatusCode != 0 {
+			httpErr = &domain.HTTPError{URL: pageURL, StatusCode: r.StatusCode}
+		}
+	})
 
-	if err := b.c.Visit(b.baseURL + "/programm"); err != nil {
-		return []domain.Screening{}, fmt.Errorf("running colly: %w", err)
+	if err := c.Visit(pageURL); err != nil {
+		if errors.Is(err, httpclient.ErrNotModified) && hasLast {
+			screenings := make([]domain.Screening, len(last.screenings))
+			for i, s := range last.screenings {
+				s.UpdatedAt = now
+				screenings[i] = s
+			}
+			return babylonPage{screenings: screenings, next: last.next}, nil
+		}
+		if httpErr != nil {
+			return babylonPage{}, httpErr
+		}
+		return babylonPage{}, fmt.Errorf("running colly: %w", err)
 	}
 
-	return screenings, nil
+	// keep a copy, the caller owns the returned screenings
+	b.pages.set(pageURL, babylonPage{
+		screenings: append([]domain.Screening(nil), page.screenings...),
+		next:       page.next,
+	}, now)
+
+	return page, nil
+}
+
+func (b Babylon) newCollector() *colly.Collector {
+	config := b.client.Config()
+
+	c := colly.NewCollector(colly.UserAgent(config.UserAgent))
+	c.WithTransport(b.client.Transport())
+	c.SetRequestTimeout(config.Timeout)
+
+	return c
+}
+
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Host == ub.Host
+}
+
+func (b Babylon) parseScreening(e *colly.HTMLElement, now time.Time) (domain.Screening, bool) {
+	titles := e.ChildTexts("h3")
+	if len(titles) <= 2 {
+		return domain.Screening{}, false
+	}
+
+	date, err := parseDate(e.Attr("data-date"))
+	if err != nil {
+		log.Printf("Failed to parse date: %v", err)
+		return domain.Screening{}, false
+	}
+
+	var duration time.Duration
+	runtimeTexts := e.ChildTexts(".runtime")
+	if len(runtimeTexts) > 0 {
+		var err error
+		duration, err = parseDuration(runtimeTexts[0])
+		if err != nil {
+			log.Printf("Failed to parse duration: %v", err)
+		}
+	}
+
+	// without a link there is no detail page to enrich the screening with
+	var link string
+	if href := e.ChildAttr(".mix-title", "href"); href != "" {
+		link = e.Request.AbsoluteURL(href)
+	}
+
+	title := titles[2]
+
+	// the detail page may name the version as well, but the ID must not
+	// depend on whether it could be fetched
+	language := parseBabylonVersion(e.ChildText(".mix-introtext"))
+
+	screeningID := domain.NewScreeningID(
+		title,
+		date,
+		b.Name(),
+		language,
+	)
+
+	thumbnail := e.ChildAttr(".upper-mix img", "src")
+	if thumbnail == "" {
+		thumbnail = e.ChildAttr(".fancybox", "href")
+	}
+
+	classes := strings.Fields(e.Attr("class"))
+
+	availability := domain.AvailabilityUnknown
+	if slices.Contains(classes, "tag-ausverkauft") {
+		availability = domain.AvailabilitySoldOut
+	}
+
+	var prices domain.Prices
+	if slices.Contains(classes, "tag-eintritt-frei") {
+		prices = domain.Prices{Currency: "EUR"}
+	}
+
+	// tag and category classes like "tag-70mm" or "cat-KINDERKINO" name
+	// attributes of the screening
+	texts := []string{title, e.ChildText(".mix-introtext")}
+	for _, class := range classes {
+		if name, ok := strings.CutPrefix(class, "tag-"); ok {
+			texts = append(texts, strings.ReplaceAll(name, "-", " "))
+		} else if name, ok := strings.CutPrefix(class, "cat-"); ok {
+			texts = append(texts, strings.ReplaceAll(name, "-", " "))
+		}
+	}
+	tags := domain.DetectTags(texts...)
+
+	var notes []string
+	if series, festival := babylonSeries(e); series != "" {
+		notes = append(notes, "Reihe: "+series)
+		if festival {
+			tags = tags.With(domain.TagFestival)
+		}
+	}
+
+	return domain.Screening{
+		ID:          screeningID,
+		Title:       title,
+		Description: "",
+		Start:       date,
+		Duration:    duration,
+		Cinema:      b.Name(),
+		Language:    language,
+		Notes:       notes,
+		Tags:        tags,
+
+		Availability: availability,
+		Prices:       prices,
+		Links: domain.ScreeningLinks{
+			Details:       link,
+			ThumbnailLink: thumbnail,
+		},
+		UpdatedAt: now,
+	}, true
+}
+
+// babylonSeries returns the festival or film series a list item belongs to.
+// Plain categories like "FILM" are not series.
+func babylonSeries(e *colly.HTMLElement) (series string, festival bool) {
+	e.ForEachWithBreak(".mix-category a", func(_ int, a *colly.HTMLElement) bool {
+		href := a.Attr("href")
+		festival = strings.HasPrefix(href, "/programm/festivals/")
+		if festival || strings.HasPrefix(href, "/programm/filmreihen/") {
+			series = strings.TrimSpace(a.Text)
+			return false
+		}
+		return true
+	})
+	return series, festival
 }
 
 func parseDate(dateString string) (time.Time, error) {
@@ -0,0 +1,304 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpclient"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// babylonDetailTTL is how long a parsed detail page is used without asking
+// the website again. Detail pages rarely change once published.
+const babylonDetailTTL = 12 * time.Hour
+
+// babylonStatusMaxLen is the maximum length of a paragraph announcing the
+// availability. Longer paragraphs are descriptions that happen to contain
+// words like "sold out".
+const babylonStatusMaxLen = 80
+
+// babylonDetail is what a detail page adds to a list item.
+type babylonDetail struct {
+	description string
+	director    string
+	year        int
+	country     string
+	version     string
+	booking     string
+	notes       []string
+	tags        domain.Tags
+	// prices are unknown unless the page names them.
+	prices domain.Prices
+	// availability is unknown unless the page announces it.
+	availability domain.Availability
+}
+
+var (
+	// babylonCreditsRe matches credits like "I 1963, R: Francesco Rosi" or
+	// "FR, DE, BE 2015, R: Paul Verhoeven mit Isabelle Huppert".
+	babylonCreditsRe = regexp.MustCompile(`((?:[A-Z]{1,4}\s*[,/]\s*)*[A-Z]{1,4})\s*,?\s*((?:18|19|20)\d{2})\s*[,;]\s*R:\s*([^,]+?)(?:\s+mit\s|\s*,|\s*…|\s*$)`)
+
+	// babylonVersionRe matches versions like "[OmU]" or a trailing ", OmeU".
+	// OF and DF are only accepted in brackets, as they appear in titles.
+	babylonVersionRe = regexp.MustCompile(`\[(OmU|OmeU|OmdU|OmenglU|OV|OF|DF)\]|\b(OmU|OmeU|OmdU|OmenglU|OV)\b`)
+
+	// babylonSoldOutRe and babylonFewSeatsRe match short status paragraphs
+	// like "AUSVERKAUFT!" or "Nur noch Restkarten an der Abendkasse".
+	babylonSoldOutRe  = regexp.MustCompile(`(?i)ausverkauft|sold out`)
+	babylonFewSeatsRe = regexp.MustCompile(`(?i)restkarten|nur noch wenige|wenige (?:karten|tickets|plätze)|few (?:tickets|seats)`)
+
+	// babylonPriceRe matches prices like "Eintritt: 9,50 € / ermäßigt 7 €"
+	// and babylonFreeRe free admission.
+	babylonPriceRe = regexp.MustCompile(`(?i)(?:eintritt|tickets?|preis)\s*:?\s*(\d+(?:[.,]\d{1,2})?)\s*(?:€|eur\b)(?:.*?erm(?:äßigt|\.)\s*:?\s*(\d+(?:[.,]\d{1,2})?)\s*(?:€|eur\b))?`)
+	babylonFreeRe  = regexp.MustCompile(`(?i)eintritt\s+frei|freier eintritt|free (?:entry|admission)`)
+
+	// babylonNoteRe matches paragraphs announcing something besides the film.
+	babylonNoteRe = regexp.MustCompile(`(?i)zu gast|gast:|q\s*&\s*a|filmgespräch|publikumsgespräch|einführung|in anwesenheit|live-musik|live music|begleitet vo[mn]`)
+)
+
+var babylonVersions = map[string]string{
+	"omu":     "OmU",
+	"omeu":    "OmeU",
+	"omenglu": "OmeU",
+	"omdu":    "OmdU",
+	"ov":      "OV",
+	"of":      "OV",
+	"df":      "DF",
+}
+
+// parseBabylonVersion returns the normalised version (OmU, OmeU, OV, ...)
+// named in text, or an empty string.
+func parseBabylonVersion(text string) string {
+	m := babylonVersionRe.FindStringSubmatch(text)
+	if m == nil {
+		return ""
+	}
+	version := m[1]
+	if version == "" {
+		version = m[2]
+	}
+	return babylonVersions[strings.ToLower(version)]
+}
+
+// parseBabylonCredits extracts country, year and director from credits.
+func parseBabylonCredits(text string) (country string, year int, director string, ok bool) {
+	m := babylonCreditsRe.FindStringSubmatch(text)
+	if m == nil {
+		return "", 0, "", false
+	}
+
+	countries := strings.FieldsFunc(m[1], func(r rune) bool {
+		return r == ',' || r == '/'
+	})
+	for i, c := range countries {
+		countries[i] = strings.TrimSpace(c)
+	}
+
+	year, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, "", false
+	}
+
+	return strings.Join(countries, "/"), year, strings.TrimSpace(m[3]), true
+}
+
+// parseBabylonDetail parses the article of a detail page.
+func parseBabylonDetail(doc *goquery.Selection, pageURL func(string) string) babylonDetail {
+	var (
+		detail      babylonDetail
+		description []string
+		// texts that may name tags, descriptions are too vague
+		tagTexts []string
+	)
+
+	article := doc.Find(`[itemprop="articleBody"]`).First()
+
+	article.Find("a").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		href, _ := a.Attr("href")
+		text := strings.ToLower(a.Text())
+		if strings.Contains(href, "kinoheld") || strings.Contains(href, "ticket") || strings.Contains(text, "ticket") {
+			detail.booking = pageURL(href)
+			return false
+		}
+		return true
+	})
+
+	article.Find("p").Each(func(_ int, p *goquery.Selection) {
+		text := collapseSpace(p.Text())
+		switch {
+		case text == "":
+		case detail.director == "" && babylonCreditsRe.MatchString(text):
+			detail.country, detail.year, detail.director, _ = parseBabylonCredits(text)
+			detail.version = parseBabylonVersion(text)
+			tagTexts = append(tagTexts, text)
+		case p.Find(`a[href*="kinoheld"], a[href*="ticket"]`).Length() > 0 && len(text) < 40:
+			// the ticket button
+		case len(text) < babylonStatusMaxLen && babylonFreeRe.MatchString(text):
+			detail.prices = domain.Prices{Currency: "EUR"}
+		case len(text) < babylonStatusMaxLen && babylonPriceRe.MatchString(text):
+			detail.prices = parseBabylonPrices(text)
+		case len(text) < babylonStatusMaxLen && babylonSoldOutRe.MatchString(text):
+			detail.availability = domain.AvailabilitySoldOut
+		case len(text) < babylonStatusMaxLen && babylonFewSeatsRe.MatchString(text):
+			detail.availability = domain.AvailabilityFewSeats
+		case babylonNoteRe.MatchString(text):
+			detail.notes = append(detail.notes, text)
+			tagTexts = append(tagTexts, text)
+		default:
+			description = append(description, text)
+		}
+	})
+	detail.description = strings.Join(description, "\n\n")
+	detail.tags = domain.DetectTags(tagTexts...)
+
+	if detail.availability == domain.AvailabilityUnknown && detail.booking != "" {
+		detail.availability = domain.AvailabilityAvailable
+	}
+
+	return detail
+}
+
+func parseBabylonPrices(text string) domain.Prices {
+	m := babylonPriceRe.FindStringSubmatch(text)
+	if m == nil {
+		return domain.Prices{}
+	}
+
+	prices := domain.Prices{Currency: "EUR"}
+	// the regular expression only matches valid amounts
+	prices.Regular, _ = domain.ParseMoney(m[1])
+	if m[2] != "" {
+		prices.Reduced, _ = domain.ParseMoney(m[2])
+	}
+
+	return prices
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// addDetails enriches screenings with their detail pages. Pages that cannot
+// be fetched are logged and leave the screenings as they are.
+func (b Babylon) addDetails(screenings []domain.Screening, now time.Time) {
+	var urls []string
+	for _, s := range screenings {
+		if s.Links.Details != "" && !slices.Contains(urls, s.Links.Details) {
+			urls = append(urls, s.Links.Details)
+		}
+	}
+
+	details := make(map[string]babylonDetail, len(urls))
+	var mu sync.Mutex
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range max(b.detailWorkers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range jobs {
+				detail, err := b.detail(url, now)
+				if err != nil {
+					log.Printf("Failed to scrape Babylon detail page: %v", err)
+					continue
+				}
+
+				mu.Lock()
+				details[url] = detail
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, url := range urls {
+		jobs <- url
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := range screenings {
+		detail, ok := details[screenings[i].Links.Details]
+		if !ok {
+			continue
+		}
+
+		s := &screenings[i]
+		s.Description = detail.description
+		s.Director = detail.director
+		s.Year = detail.year
+		s.Country = detail.country
+		s.Links.Booking = detail.booking
+		s.Tags = s.Tags.With(detail.tags...)
+		if !s.Prices.Known() {
+			s.Prices = detail.prices
+		}
+		if s.Availability == domain.AvailabilityUnknown {
+			s.Availability = detail.availability
+		}
+		if s.Language == "" {
+			s.Language = detail.version
+		}
+		for _, note := range detail.notes {
+			if !slices.Contains(s.Notes, note) {
+				s.Notes = append(s.Notes, note)
+			}
+		}
+	}
+}
+
+// detail returns the parsed detail page at url. Recently parsed pages are
+// answered from memory, older ones are revalidated.
+func (b Babylon) detail(url string, now time.Time) (babylonDetail, error) {
+	last, fetchedAt, hasLast := b.details.get(url)
+	if hasLast && now.Sub(fetchedAt) < babylonDetailTTL {
+		return last, nil
+	}
+
+	c := b.newCollector()
+	if hasLast {
+		c.Context = httpclient.WithConditional(c.Context)
+	}
+
+	var (
+		detail babylonDetail
+		found  bool
+	)
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		detail = parseBabylonDetail(e.DOM, e.Request.AbsoluteURL)
+		found = true
+	})
+
+	var httpErr error
+	c.OnError(func(r *colly.Response, err error) {
+		if r.StatusCode != 0 {
+			httpErr = &domain.HTTPError{URL: url, StatusCode: r.StatusCode}
+		}
+	})
+
+	if err := c.Visit(url); err != nil {
+		if errors.Is(err, httpclient.ErrNotModified) && hasLast {
+			b.details.set(url, last, now)
+			return last, nil
+		}
+		if httpErr != nil {
+			return babylonDetail{}, httpErr
+		}
+		return babylonDetail{}, fmt.Errorf("visiting %s: %w", url, err)
+	}
+	if !found {
+		return babylonDetail{}, fmt.Errorf("%s is not an HTML page", url)
+	}
+
+	b.details.set(url, detail, now)
+
+	return detail, nil
+}
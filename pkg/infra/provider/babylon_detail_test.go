@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParseBabylonCredits(t *testing.T) {
+	tests := []struct {
+		text         string
+		wantCountry  string
+		wantYear     int
+		wantDirector string
+		wantVersion  string
+	}{
+		{"Cinema! Italia!: Le mani sulla città [Hände über der Stadt] [OmU] I 1963, R: Francesco Rosi", "I", 1963, "Francesco Rosi", "OmU"},
+		{"D 1987, R: Wim Wenders, mit Bruno Ganz, Solveig Dommartin, Otto Sander, 128 Min, OmeU", "D", 1987, "Wim Wenders", "OmeU"},
+		{"Isabelle Huppert: Elle [OmeU] FR, DE, BE 2015, R: Paul Verhoeven mit Isabelle Huppert, Laurent", "FR/DE/BE", 2015, "Paul Verhoeven", "OmeU"},
+		{"Isabelle Huppert: La Pianiste [OmU] FR, DE, AT , 2001, R: Michael Haneke", "FR/DE/AT", 2001, "Michael Haneke", "OmU"},
+		{"Donnie Darko, USA 2001, R: Richard Kelly mit Jake Gyllenhaal", "USA", 2001, "Richard Kelly", ""},
+		{"THE LORD OF THE RINGS [DF]", "", 0, "", "DF"},
+		{"DMP Cinema and Babylon are excited to present .. THE DMP FRIDAY NIGHT FRIGHT SHOW!", "", 0, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			country, year, director, _ := parseBabylonCredits(tt.text)
+			if country != tt.wantCountry || year != tt.wantYear || director != tt.wantDirector {
+				t.Errorf("parseBabylonCredits() = %q, %d, %q, want %q, %d, %q",
+					country, year, director, tt.wantCountry, tt.wantYear, tt.wantDirector)
+			}
+			if got := parseBabylonVersion(tt.text); got != tt.wantVersion {
+				t.Errorf("parseBabylonVersion() = %q, want %q", got, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestParseBabylonDetail(t *testing.T) {
+	tests := []struct {
+		file string
+		want babylonDetail
+	}{
+		{
+			file: "testdata/babylon_detail_9000.html",
+			want: babylonDetail{
+				description: "Die Zukunftsstadt Metropolis ist streng geteilt: Oben leben die Reichen in Luxus, in der Tiefe schuften die Arbeiter an den Maschinen.\n\n" +
+					"Freder, der Sohn des Herrschers über Metropolis, verliebt sich in die Arbeiterin Maria.",
+				director: "Fritz Lang",
+				year:     1927,
+				country:  "D",
+				booking:  "https://www.kinoheld.de/kino/berlin/kino-babylon-berlin-mitte/vorstellung/9000",
+				notes:    []string{"Live-Musik: Anna Vavilkina an der Philips-Kinoorgel"},
+				tags:     domain.Tags{domain.Tag35mm},
+				prices:   domain.Prices{Currency: "EUR", Regular: 1400, Reduced: 1150},
+
+				availability: domain.AvailabilityAvailable,
+			},
+		},
+		{
+			file: "testdata/babylon_detail_9500.html",
+			want: babylonDetail{
+				description: "Le mani sulla città [Hände über der Stadt] [OmU]\n\n" +
+					"Neapel in den frühen Sechzigern: Der Bauunternehmer und Stadtrat Edoardo Nottola nutzt seinen politischen Einfluss, um mit Spekulationen ein Vermögen zu machen.",
+				director: "Francesco Rosi",
+				year:     1963,
+				country:  "I/F",
+				booking:  "https://babylonberlin.eu/tickets/9500",
+				notes:    []string{"Einführung und Filmgespräch mit der Filmhistorikerin Giulia Rossi."},
+				tags:     domain.Tags{domain.TagGuests},
+
+				availability: domain.AvailabilityAvailable,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			doc, err := goquery.NewDocumentFromReader(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			base, _ := url.Parse("https://babylonberlin.eu/programm/")
+			resolve := func(href string) string {
+				ref, _ := url.Parse(href)
+				return base.ResolveReference(ref).String()
+			}
+
+			if got := parseBabylonDetail(doc.Selection, resolve); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBabylonDetail() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBabylonDetail_Availability(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want domain.Availability
+	}{
+		{"no status", `<p>Ein Film.</p>`, domain.AvailabilityUnknown},
+		{"ticket link", `<p><a href="https://www.kinoheld.de/x">Tickets</a></p>`, domain.AvailabilityAvailable},
+		{"sold out", `<p><strong>AUSVERKAUFT!</strong></p><p><a href="https://www.kinoheld.de/x">Tickets</a></p>`, domain.AvailabilitySoldOut},
+		{"few seats", `<p>Nur noch Restkarten an der Abendkasse</p>`, domain.AvailabilityFewSeats},
+		{"price is no status", `<p>Eintritt: 9 €</p>`, domain.AvailabilityUnknown},
+		{
+			"description mentioning sold out",
+			`<p>Als der Film 1975 in die Kinos kam, waren die Vorstellungen wochenlang ausverkauft und die Kritik überschlug sich.</p>`,
+			domain.AvailabilityUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div itemprop="articleBody">` + tt.body + `</div>`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := parseBabylonDetail(doc.Selection, func(href string) string { return href })
+			if got.availability != tt.want {
+				t.Errorf("availability = %v, want %v", got.availability, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBabylonPrices(t *testing.T) {
+	tests := []struct {
+		text string
+		want domain.Prices
+	}{
+		{"Eintritt: 9,50 € / ermäßigt 7,50 €", domain.Prices{Currency: "EUR", Regular: 950, Reduced: 750}},
+		{"Tickets 12 EUR, erm. 10 EUR", domain.Prices{Currency: "EUR", Regular: 1200, Reduced: 1000}},
+		{"Eintritt 8€", domain.Prices{Currency: "EUR", Regular: 800}},
+		{"Ein Film über 9 Leben", domain.Prices{}},
+	}
+
+	for _, tt := range tests {
+		if got := parseBabylonPrices(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseBabylonPrices(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestBabylon_ScrapeDetails(t *testing.T) {
+	var detailRequests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /programm", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/babylon_paged.html")
+	})
+	mux.HandleFunc("GET /programm/stummfilm/9000-metropolis", func(w http.ResponseWriter, r *http.Request) {
+		detailRequests++
+		http.ServeFile(w, r, "testdata/babylon_detail_9000.html")
+	})
+	b := newTestBabylon(t, mux)
+
+	for range 2 {
+		screenings, err := b.Scrape()
+		if err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+		if len(screenings) != 1 {
+			t.Fatalf("Scrape() returned %d screenings, want 1", len(screenings))
+		}
+
+		s := screenings[0]
+		if s.Director != "Fritz Lang" || s.Year != 1927 || s.Country != "D" {
+			t.Errorf("credits = %q, %d, %q, want %q, %d, %q", s.Director, s.Year, s.Country, "Fritz Lang", 1927, "D")
+		}
+		if s.Description == "" {
+			t.Error("Description is empty")
+		}
+		if s.Links.Booking != "https://www.kinoheld.de/kino/berlin/kino-babylon-berlin-mitte/vorstellung/9000" {
+			t.Errorf("Links.Booking = %q", s.Links.Booking)
+		}
+		if s.Availability != domain.AvailabilityAvailable {
+			t.Errorf("Availability = %v, want %v", s.Availability, domain.AvailabilityAvailable)
+		}
+		wantNotes := []string{"Live-Musik: Anna Vavilkina an der Philips-Kinoorgel"}
+		if !reflect.DeepEqual(s.Notes, wantNotes) {
+			t.Errorf("Notes = %q, want %q", s.Notes, wantNotes)
+		}
+	}
+
+	// the detail page is cached between scrapes
+	if detailRequests != 1 {
+		t.Errorf("detail page requested %d times, want 1", detailRequests)
+	}
+}
@@ -1,9 +1,53 @@
 package provider
 
 import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpclient"
 )
 
+func newTestBabylon(t *testing.T, handler http.Handler) *Babylon {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	b := newBabylon(httpclient.New(httpclient.Config{MinInterval: time.Millisecond}))
+	b.baseURL = srv.URL
+	return b
+}
+
+// serveWithBase serves a saved page of the website with its base URL
+// pointing to the test server, which links are resolved against.
+func serveWithBase(t *testing.T, w http.ResponseWriter, r *http.Request, file string) {
+	page, err := os.ReadFile(file)
+	if err != nil {
+		t.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page = bytes.ReplaceAll(page, []byte(`<base href="https://babylonberlin.eu`), []byte(`<base href="http://`+r.Host))
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+// withoutUpdatedAt clears UpdatedAt so that results of different scrapes can
+// be compared.
+func withoutUpdatedAt(screenings []domain.Screening) []domain.Screening {
+	cleared := make([]domain.Screening, len(screenings))
+	for i, s := range screenings {
+		s.UpdatedAt = time.Time{}
+		cleared[i] = s
+	}
+	return cleared
+}
+
 func TestBabylon_Name(t *testing.T) {
 	b := NewBabylon()
 	if got := b.Name(); got != "Kino Babylon" {
@@ -12,5 +56,108 @@ func TestBabylon_Name(t *testing.T) {
 }
 
 func TestBabylon_Scrape(t *testing.T) {
-	// TODO
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /programm", func(w http.ResponseWriter, r *http.Request) {
+		serveWithBase(t, w, r, "testdata/babylon.html")
+	})
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		// all detail pages
+		http.ServeFile(w, r, "testdata/babylon_detail_9500.html")
+	})
+	b := newTestBabylon(t, mux)
+
+	first, err := b.Scrape()
+	if err != nil {
+		t.Fatalf("first Scrape() error = %v", err)
+	}
+	if len(first) == 0 {
+		t.Fatal("first Scrape() returned no screenings")
+	}
+
+	s := first[0]
+	wantStart := time.Date(2025, 12, 31, 17, 30, 0, 0, berlinLocation(t))
+	if s.Title != "Cinema! Italia!: Le mani sulla città" || !s.Start.Equal(wantStart) || s.Duration != 105*time.Minute {
+		t.Errorf("first screening = %q at %v (%v), want %q at %v (%v)",
+			s.Title, s.Start, s.Duration, "Cinema! Italia!: Le mani sulla città", wantStart, 105*time.Minute)
+	}
+	if s.Language != "OmU" || s.Director != "Francesco Rosi" {
+		t.Errorf("first screening language and director = %q, %q, want %q, %q", s.Language, s.Director, "OmU", "Francesco Rosi")
+	}
+	if wantTags := (domain.Tags{domain.TagGuests, domain.TagFestival}); !reflect.DeepEqual(s.Tags, wantTags) {
+		t.Errorf("first screening tags = %v, want %v", s.Tags, wantTags)
+	}
+	if wantNote := "Reihe: CINEMA! ITALIA!"; len(s.Notes) == 0 || s.Notes[0] != wantNote {
+		t.Errorf("first screening notes = %q, want %q first", s.Notes, wantNote)
+	}
+
+	var free int
+	for _, s := range first {
+		if s.Prices.Known() && s.Prices.Regular == 0 {
+			free++
+		}
+	}
+	if free == 0 {
+		t.Error("no free screenings, want those tagged eintritt-frei")
+	}
+
+	// a second sync must neither return nothing nor duplicate screenings
+	second, err := b.Scrape()
+	if err != nil {
+		t.Fatalf("second Scrape() error = %v", err)
+	}
+	if !reflect.DeepEqual(withoutUpdatedAt(first), withoutUpdatedAt(second)) {
+		t.Errorf("second Scrape() returned %d screenings, differing from the %d of the first", len(second), len(first))
+	}
+}
+
+func TestBabylon_ScrapePages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /programm", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") == "2" {
+			serveWithBase(t, w, r, "testdata/babylon.html")
+			return
+		}
+		http.ServeFile(w, r, "testdata/babylon_paged.html")
+	})
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/babylon_detail_9500.html")
+	})
+	b := newTestBabylon(t, mux)
+
+	screenings, err := b.Scrape()
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if len(screenings) < 2 {
+		t.Fatalf("Scrape() returned %d screenings, want screenings of both pages", len(screenings))
+	}
+	if screenings[0].Title != "Metropolis" {
+		t.Errorf("first screening = %q, want %q", screenings[0].Title, "Metropolis")
+	}
+	if screenings[1].Title != "Cinema! Italia!: Le mani sulla città" {
+		t.Errorf("second screening = %q, want the first of the next page", screenings[1].Title)
+	}
+}
+
+func TestBabylon_ScrapeHTTPError(t *testing.T) {
+	b := newTestBabylon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+
+	_, err := b.Scrape()
+
+	var httpErr *domain.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Scrape() error = %v, want HTTPError with status 503", err)
+	}
+}
+
+func berlinLocation(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
 }
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpclient"
+)
+
+// lastResult keeps the screenings of the last successful scrape of a source,
+// so that an unchanged source (HTTP 304) is answered without parsing it again.
+type lastResult struct {
+	mu         sync.Mutex
+	screenings []domain.Screening
+	ok         bool
+}
+
+// context returns a context for requests to the source. Requests are only
+// conditional if there is a previous result to fall back to.
+func (l *lastResult) context() context.Context {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.ok {
+		return context.Background()
+	}
+	return httpclient.WithConditional(context.Background())
+}
+
+func (l *lastResult) set(screenings []domain.Screening) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.screenings = screenings
+	l.ok = true
+}
+
+// refreshed returns a copy of the last result updated at now.
+func (l *lastResult) refreshed(now time.Time) []domain.Screening {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	screenings := make([]domain.Screening, len(l.screenings))
+	for i, s := range l.screenings {
+		s.UpdatedAt = now
+		screenings[i] = s
+	}
+
+	return screenings
+}
+
+// resultCache keeps the last result per URL, for providers that scrape
+// several pages per source.
+type resultCache[T any] struct {
+	mu      sync.Mutex
+	results map[string]cachedResult[T]
+}
+
+type cachedResult[T any] struct {
+	value     T
+	fetchedAt time.Time
+}
+
+func newResultCache[T any]() *resultCache[T] {
+	return &resultCache[T]{results: make(map[string]cachedResult[T])}
+}
+
+// get returns the last result of url and when it was fetched.
+func (c *resultCache[T]) get(url string) (value T, fetchedAt time.Time, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, ok := c.results[url]
+	return r.value, r.fetchedAt, ok
+}
+
+func (c *resultCache[T]) set(url string, value T, fetchedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.results[url] = cachedResult[T]{value: value, fetchedAt: fetchedAt}
+}
@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpclient"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/provider/icalmodel"
+)
+
+// ICal reads screenings from iCalendar (.ics) programme feeds. Sources can be
+// http(s) or webcal URLs as well as paths to local files.
+type ICal struct {
+	name    string
+	sources []string
+	client  *http.Client
+	// horizon limits how far into the future recurring events are expanded.
+	horizon time.Duration
+	// calendars keeps the last parsed calendar per source for conditional
+	// requests.
+	calendars *calendarCache
+}
+
+type calendarCache struct {
+	mu        sync.Mutex
+	calendars map[string]icalmodel.Calendar
+}
+
+func (c *calendarCache) get(source string) (icalmodel.Calendar, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cal, ok := c.calendars[source]
+	return cal, ok
+}
+
+func (c *calendarCache) set(source string, cal icalmodel.Calendar) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calendars[source] = cal
+}
+
+var _ domain.Provider = &ICal{}
+
+func init() {
+	Register("ical", func(opts Options) (domain.Provider, error) {
+		if len(opts.Sources) == 0 {
+			return nil, fmt.Errorf("no sources configured")
+		}
+		name := opts.Name
+		if name == "" {
+			name = "iCal"
+		}
+		return newICal(opts.client(), name, opts.Sources...), nil
+	})
+}
+
+func NewICal(name string, sources ...string) *ICal {
+	return newICal(httpclient.New(httpclient.Config{}), name, sources...)
+}
+
+func newICal(client *httpclient.Client, name string, sources ...string) *ICal {
+	return &ICal{
+		name:      name,
+		sources:   sources,
+		client:    client.HTTP(),
+		horizon:   90 * 24 * time.Hour,
+		calendars: &calendarCache{calendars: make(map[string]icalmodel.Calendar)},
+	}
+}
+
+func (i ICal) Name() string {
+	return i.name
+}
+
+func (i ICal) Scrape() ([]domain.Screening, error) {
+	tz, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return []domain.Screening{}, fmt.Errorf("creating timezone: %w", err)
+	}
+
+	now := time.Now()
+	from := now.Add(-24 * time.Hour)
+	to := now.Add(i.horizon)
+
+	var screenings []domain.Screening
+	for _, source := range i.sources {
+		cal, err := i.fetch(source, tz)
+		if err != nil {
+			return []domain.Screening{}, fmt.Errorf("reading %q: %w", source, err)
+		}
+
+		screenings = append(screenings, i.screenings(cal, from, to, now)...)
+	}
+
+	return screenings, nil
+}
+
+func (i ICal) fetch(source string, tz *time.Location) (icalmodel.Calendar, error) {
+	var r io.ReadCloser
+
+	switch {
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"),
+		strings.HasPrefix(source, "webcal://"):
+		address := strings.Replace(source, "webcal://", "https://", 1)
+
+		ctx := context.Background()
+		cached, ok := i.calendars.get(source)
+		if ok {
+			ctx = httpclient.WithConditional(ctx)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+		if err != nil {
+			return icalmodel.Calendar{}, fmt.Errorf("creating request: %w", err)
+		}
+		res, err := i.client.Do(req)
+		if errors.Is(err, httpclient.ErrNotModified) {
+			return cached, nil
+		}
+		if err != nil {
+			return icalmodel.Calendar{}, fmt.Errorf("fetching: %w", err)
+		}
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return icalmodel.Calendar{}, &domain.HTTPError{URL: address, StatusCode: res.StatusCode}
+		}
+		r = res.Body
+	default:
+		f, err := os.Open(strings.TrimPrefix(source, "file://"))
+		if err != nil {
+			return icalmodel.Calendar{}, fmt.Errorf("opening file: %w", err)
+		}
+		r = f
+	}
+	defer r.Close()
+
+	cal, err := icalmodel.Parse(r, tz)
+	if err != nil {
+		return icalmodel.Calendar{}, fmt.Errorf("parsing calendar: %w", err)
+	}
+	for _, err := range cal.Skipped {
+		log.Printf("Skipped event of %s: %v", source, err)
+	}
+	i.calendars.set(source, cal)
+
+	return cal, nil
+}
+
+func (i ICal) screenings(cal icalmodel.Calendar, from, to, now time.Time) []domain.Screening {
+	// instances that were moved or changed are published as separate events
+	// carrying a RECURRENCE-ID; they replace the regular instance
+	overridden := make(map[string][]time.Time)
+	for _, e := range cal.Events {
+		if !e.RecurrenceID.IsZero() {
+			overridden[e.UID] = append(overridden[e.UID], e.RecurrenceID)
+		}
+	}
+
+	var screenings []domain.Screening
+	for _, e := range cal.Events {
+		if e.AllDay || e.Summary == "" {
+			continue
+		}
+
+		if e.RecurrenceID.IsZero() {
+			e.ExDates = append(e.ExDates, overridden[e.UID]...)
+		}
+
+		duration := e.End.Sub(e.Start)
+		if duration < 0 {
+			duration = 0
+		}
+
+		cinema := e.Location
+		if cinema == "" {
+			cinema = i.name
+		}
+
+		for _, start := range e.Occurrences(from, to) {
+			language := ""
+			screenings = append(screenings, domain.Screening{
+				ID:          domain.NewScreeningID(e.Summary, start, cinema, language),
+				Title:       e.Summary,
+				Description: e.Description,
+				Start:       start,
+				Duration:    duration,
+				Cinema:      cinema,
+				Language:    language,
+				Links: domain.ScreeningLinks{
+					Details: e.URL,
+				},
+				UpdatedAt: now,
+			})
+		}
+	}
+
+	return screenings
+}
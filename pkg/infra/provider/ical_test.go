@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/provider/icalmodel"
+)
+
+func TestICal_Name(t *testing.T) {
+	i := NewICal("Festival Feed")
+	if got := i.Name(); got != "Festival Feed" {
+		t.Errorf("Name() = %q, want %q", got, "Festival Feed")
+	}
+}
+
+func TestICal_Screenings(t *testing.T) {
+	tz, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open("testdata/programme.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cal, err := icalmodel.Parse(f, tz)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	i := NewICal("Festival Feed")
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, tz)
+	to := time.Date(2025, 12, 1, 0, 0, 0, 0, tz)
+	screenings := i.screenings(cal, from, to, time.Now())
+
+	type want struct {
+		title    string
+		start    time.Time
+		duration time.Duration
+		cinema   string
+	}
+	wants := []want{
+		{"Metropolis", time.Date(2025, 10, 31, 20, 0, 0, 0, tz), 153 * time.Minute, "Kino Babylon"},
+		{"Late Night Shorts", time.Date(2025, 11, 1, 22, 30, 0, 0, time.UTC), 105 * time.Minute, "Festival Feed"},
+		// the weekly series crosses the end of daylight saving time on
+		// 26.10., the 23.10. is excluded and the 28.10. is moved to 21:00
+		{"Filmclub", time.Date(2025, 10, 21, 20, 0, 0, 0, tz), 2 * time.Hour, "Delphi Lux"},
+		{"Filmclub", time.Date(2025, 10, 30, 20, 0, 0, 0, tz), 2 * time.Hour, "Delphi Lux"},
+		{"Filmclub (Sondervorstellung)", time.Date(2025, 10, 28, 21, 0, 0, 0, tz), 2 * time.Hour, "Delphi Lux"},
+	}
+
+	if len(screenings) != len(wants) {
+		t.Fatalf("got %d screenings, want %d: %+v", len(screenings), len(wants), screenings)
+	}
+	for n, w := range wants {
+		s := screenings[n]
+		if s.Title != w.title || !s.Start.Equal(w.start) || s.Duration != w.duration || s.Cinema != w.cinema {
+			t.Errorf("screening %d = {%q %v %v %q}, want {%q %v %v %q}",
+				n, s.Title, s.Start, s.Duration, s.Cinema, w.title, w.start, w.duration, w.cinema)
+		}
+	}
+
+	if got, want := screenings[0].Description, "Stummfilm mit Live-Orchester, restaurierte Fassung.\nEinführung vor dem Film."; got != want {
+		t.Errorf("Description = %q, want %q", got, want)
+	}
+	if got, want := screenings[0].Links.Details, "https://kino.test/metropolis"; got != want {
+		t.Errorf("Links.Details = %q, want %q", got, want)
+	}
+}
+
+func TestICal_SkipsUnsupportedEvents(t *testing.T) {
+	feed := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:monthly@kino.test",
+		"SUMMARY:Stummfilmmatinee",
+		"DTSTART;TZID=Europe/Berlin:20251004T110000",
+		"RRULE:FREQ=MONTHLY;BYDAY=1SA",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:hourly@kino.test",
+		"SUMMARY:Loop",
+		"DTSTART:20251004T110000Z",
+		"RRULE:FREQ=HOURLY",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:single@kino.test",
+		"SUMMARY:Metropolis",
+		"DTSTART;TZID=Europe/Berlin:20251031T200000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	cal, err := icalmodel.Parse(strings.NewReader(feed), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(cal.Events) != 1 || cal.Events[0].UID != "single@kino.test" {
+		t.Errorf("Events = %+v, want only single@kino.test", cal.Events)
+	}
+	if len(cal.Skipped) != 2 {
+		t.Errorf("Skipped = %v, want the monthly and the hourly event", cal.Skipped)
+	}
+}
+
+func TestICal_ParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"PT1H45M", 105 * time.Minute},
+		{"P1DT2H", 26 * time.Hour},
+		{"PT90S", 90 * time.Second},
+		{"-PT15M", -15 * time.Minute},
+		{"P1W", 7 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := icalmodel.ParseDuration(tt.in)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := icalmodel.ParseDuration("1H"); err == nil {
+		t.Errorf("ParseDuration(%q) expected error", "1H")
+	}
+}
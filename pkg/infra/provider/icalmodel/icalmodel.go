@@ -0,0 +1,340 @@
+package icalmodel
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Calendar struct {
+	Events []Event
+	// Skipped holds why events were left out, e.g. because of an
+	// unsupported recurrence rule. The rest of the calendar is still read.
+	Skipped []error
+}
+
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Start        time.Time
+	End          time.Time
+	Duration     time.Duration
+	AllDay       bool
+	RRule        *RRule
+	RDates       []time.Time
+	ExDates      []time.Time
+	RecurrenceID time.Time
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads an iCalendar (RFC 5545) stream and returns its VEVENTs.
+// Events with properties that cannot be parsed are skipped, see
+// Calendar.Skipped.
+//
+// Floating date-times (without "Z" suffix or TZID parameter) are interpreted
+// in defaultLoc.
+func Parse(r io.Reader, defaultLoc *time.Location) (Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	var (
+		cal     Calendar
+		current *Event
+		// invalid is the first error in the current event
+		invalid error
+		depth   []string
+	)
+
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		prop, err := parseProperty(line)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			depth = append(depth, strings.ToUpper(prop.value))
+			if strings.EqualFold(prop.value, "VEVENT") {
+				current = &Event{}
+			}
+			continue
+		case "END":
+			if len(depth) == 0 {
+				return Calendar{}, fmt.Errorf("line %d: unexpected END:%s", i+1, prop.value)
+			}
+			depth = depth[:len(depth)-1]
+			if strings.EqualFold(prop.value, "VEVENT") && current != nil {
+				if current.End.IsZero() && current.Duration > 0 {
+					current.End = current.Start.Add(current.Duration)
+				}
+				if invalid != nil {
+					cal.Skipped = append(cal.Skipped, fmt.Errorf("event %q: %w", current.UID, invalid))
+				} else {
+					cal.Events = append(cal.Events, *current)
+				}
+				current, invalid = nil, nil
+			}
+			continue
+		}
+
+		// only properties directly inside a VEVENT are of interest, nested
+		// components such as VALARM are ignored
+		if current == nil || depth[len(depth)-1] != "VEVENT" {
+			continue
+		}
+
+		if err := current.apply(prop, defaultLoc); err != nil && invalid == nil {
+			invalid = fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+
+	return cal, nil
+}
+
+func (e *Event) apply(prop property, defaultLoc *time.Location) error {
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = unescapeText(prop.value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(prop.value)
+	case "LOCATION":
+		e.Location = unescapeText(prop.value)
+	case "URL":
+		e.URL = prop.value
+	case "DTSTART":
+		t, allDay, err := parseDateTime(prop, defaultLoc)
+		if err != nil {
+			return fmt.Errorf("parsing DTSTART: %w", err)
+		}
+		e.Start, e.AllDay = t, allDay
+	case "DTEND":
+		t, _, err := parseDateTime(prop, defaultLoc)
+		if err != nil {
+			return fmt.Errorf("parsing DTEND: %w", err)
+		}
+		e.End = t
+	case "DURATION":
+		d, err := ParseDuration(prop.value)
+		if err != nil {
+			return fmt.Errorf("parsing DURATION: %w", err)
+		}
+		e.Duration = d
+	case "RRULE":
+		rule, err := ParseRRule(prop.value, defaultLoc)
+		if err != nil {
+			return fmt.Errorf("parsing RRULE: %w", err)
+		}
+		e.RRule = &rule
+	case "RDATE", "EXDATE":
+		for _, v := range strings.Split(prop.value, ",") {
+			t, _, err := parseDateTime(property{params: prop.params, value: v}, defaultLoc)
+			if err != nil {
+				return fmt.Errorf("parsing %s: %w", prop.name, err)
+			}
+			if prop.name == "RDATE" {
+				e.RDates = append(e.RDates, t)
+			} else {
+				e.ExDates = append(e.ExDates, t)
+			}
+		}
+	case "RECURRENCE-ID":
+		t, _, err := parseDateTime(prop, defaultLoc)
+		if err != nil {
+			return fmt.Errorf("parsing RECURRENCE-ID: %w", err)
+		}
+		e.RecurrenceID = t
+	}
+
+	return nil
+}
+
+// unfold joins folded content lines, i.e. lines starting with a space or a
+// tab are continuations of the previous line.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+
+	return lines, nil
+}
+
+func parseProperty(line string) (property, error) {
+	// find the colon separating name and parameters from the value, colons
+	// inside quoted parameter values do not count
+	inQuotes := false
+	sep := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ':' && !inQuotes {
+			sep = i
+			break
+		}
+	}
+	if sep == -1 {
+		return property{}, fmt.Errorf("missing value in %q", line)
+	}
+
+	head, value := line[:sep], line[sep+1:]
+	parts := splitUnquoted(head, ';')
+
+	prop := property{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string),
+		value:  value,
+	}
+	for _, p := range parts[1:] {
+		key, val, ok := strings.Cut(p, "=")
+		if !ok {
+			continue
+		}
+		prop.params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+
+	return prop, nil
+}
+
+func splitUnquoted(s string, sep rune) []string {
+	var (
+		parts    []string
+		inQuotes bool
+		start    int
+	)
+	for i, r := range s {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == sep && !inQuotes:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+func unescapeText(s string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\;`, `;`, `\,`, `,`, `\n`, "\n", `\N`, "\n")
+	return replacer.Replace(s)
+}
+
+func parseDateTime(prop property, defaultLoc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, defaultLoc)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	loc := defaultLoc
+	if tzid := prop.params["TZID"]; tzid != "" {
+		// some producers prefix the IANA name with a slash
+		if l, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = l
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// ParseDuration parses an RFC 5545 duration such as "PT1H45M" or "P1DT2H".
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var (
+		total  time.Duration
+		inTime bool
+		num    int
+		hasNum bool
+	)
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num = num*10 + int(r-'0')
+			hasNum = true
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+
+		if !hasNum {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+
+		n := time.Duration(num)
+		switch {
+		case r == 'W' && !inTime:
+			total += n * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			total += n * 24 * time.Hour
+		case r == 'H' && inTime:
+			total += n * time.Hour
+		case r == 'M' && inTime:
+			total += n * time.Minute
+		case r == 'S' && inTime:
+			total += n * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		num, hasNum = 0, false
+	}
+	if hasNum {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+
+	return sign * total, nil
+}
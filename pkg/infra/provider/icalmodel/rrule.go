@@ -0,0 +1,236 @@
+package icalmodel
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds the expansion of rules without COUNT or UNTIL.
+const maxPeriods = 10000
+
+// RRule is the subset of RFC 5545 recurrence rules used by programme feeds.
+type RRule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+func ParseRRule(value string, defaultLoc *time.Location) (RRule, error) {
+	rule := RRule{Interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return RRule{}, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			switch f := Frequency(strings.ToUpper(val)); f {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = f
+			default:
+				return RRule{}, fmt.Errorf("unsupported frequency %q", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return RRule{}, fmt.Errorf("invalid interval %q", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return RRule{}, fmt.Errorf("invalid count %q", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			t, _, err := parseDateTime(property{value: val}, defaultLoc)
+			if err != nil {
+				return RRule{}, fmt.Errorf("invalid until %q: %w", val, err)
+			}
+			rule.Until = t
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				wd, ok := weekdays[strings.ToUpper(d)]
+				if !ok {
+					return RRule{}, fmt.Errorf("unsupported weekday %q", d)
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n < 1 || n > 31 {
+					return RRule{}, fmt.Errorf("unsupported month day %q", d)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		}
+	}
+
+	if rule.Freq == "" {
+		return RRule{}, fmt.Errorf("missing FREQ")
+	}
+
+	return rule, nil
+}
+
+// Occurrences returns the start times of all instances of the event that
+// begin within [from, to). Instances are computed in the wall-clock time of
+// DTSTART so that a recurring 20:00 screening stays at 20:00 across daylight
+// saving transitions.
+func (e Event) Occurrences(from, to time.Time) []time.Time {
+	var starts []time.Time
+	if e.RRule == nil {
+		starts = []time.Time{e.Start}
+	} else {
+		starts = e.RRule.expand(e.Start, to)
+	}
+	starts = append(starts, e.RDates...)
+
+	var occurrences []time.Time
+	for _, s := range starts {
+		if s.Before(from) || !s.Before(to) || e.excluded(s) {
+			continue
+		}
+		occurrences = append(occurrences, s)
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].Before(occurrences[j])
+	})
+
+	return occurrences
+}
+
+func (e Event) excluded(t time.Time) bool {
+	for _, ex := range e.ExDates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r RRule) expand(dtstart, to time.Time) []time.Time {
+	var (
+		starts []time.Time
+		count  int
+	)
+
+	for period := 0; period < maxPeriods; period++ {
+		candidates := r.period(dtstart, period)
+		for _, c := range candidates {
+			if c.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && c.After(r.Until) {
+				return starts
+			}
+			if r.Count > 0 && count >= r.Count {
+				return starts
+			}
+			if !c.Before(to) {
+				return starts
+			}
+			starts = append(starts, c)
+			count++
+		}
+	}
+
+	return starts
+}
+
+// period returns the sorted candidate instances of the n-th period.
+func (r RRule) period(dtstart time.Time, n int) []time.Time {
+	loc := dtstart.Location()
+	hour, minute, sec := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, sec, 0, loc)
+	}
+
+	var candidates []time.Time
+	switch r.Freq {
+	case Daily:
+		d := dtstart.AddDate(0, 0, n*r.Interval)
+		if r.matchesDay(d.Weekday()) {
+			candidates = append(candidates, at(d.Date()))
+		}
+	case Weekly:
+		// weeks start on Monday (the RFC 5545 default for WKST)
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		monday := dtstart.AddDate(0, 0, -offset+7*n*r.Interval)
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{dtstart.Weekday()}
+		}
+		for _, wd := range days {
+			d := monday.AddDate(0, 0, (int(wd)+6)%7)
+			candidates = append(candidates, at(d.Date()))
+		}
+	case Monthly:
+		year, month, _ := dtstart.Date()
+		first := time.Date(year, month+time.Month(n*r.Interval), 1, 0, 0, 0, 0, loc)
+		days := r.ByMonthDay
+		if len(days) == 0 {
+			days = []int{dtstart.Day()}
+		}
+		for _, day := range days {
+			c := at(first.Year(), first.Month(), day)
+			// skip days that do not exist in this month
+			if c.Month() == first.Month() && r.matchesDay(c.Weekday()) {
+				candidates = append(candidates, c)
+			}
+		}
+	case Yearly:
+		year, month, day := dtstart.Date()
+		c := at(year+n*r.Interval, month, day)
+		if c.Month() == month {
+			candidates = append(candidates, c)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Before(candidates[j])
+	})
+
+	return candidates
+}
+
+func (r RRule) matchesDay(wd time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d == wd {
+			return true
+		}
+	}
+	return false
+}
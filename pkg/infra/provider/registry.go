@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpcache"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpclient"
+)
+
+// Options configures a provider created through the registry. Zero values
+// keep the provider's defaults.
+type Options struct {
+	// Name overrides the display name of providers that support it.
+	Name      string
+	BaseURL   string
+	Timeout   time.Duration
+	UserAgent string
+	// MinInterval is the minimum delay between requests to the same host.
+	MinInterval time.Duration
+	// IgnoreRobots disables robots.txt checks.
+	IgnoreRobots bool
+	// Cache stores responses on disk. Optional.
+	Cache *httpcache.Store
+	// Sources lists feed URLs or files for feed based providers like iCal and
+	// the programme paths for Babylon.
+	Sources []string
+}
+
+func (o Options) client() *httpclient.Client {
+	return httpclient.New(httpclient.Config{
+		UserAgent:    o.UserAgent,
+		Timeout:      o.Timeout,
+		MinInterval:  o.MinInterval,
+		IgnoreRobots: o.IgnoreRobots,
+		Cache:        o.Cache,
+	})
+}
+
+// Factory creates a provider from options.
+type Factory func(Options) (domain.Provider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a provider available under id. It panics if id is
+// registered twice.
+func Register(id string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[id]; ok {
+		panic(fmt.Sprintf("provider %q registered twice", id))
+	}
+	registry[id] = factory
+}
+
+// New creates the provider registered under id.
+func New(id string, opts Options) (domain.Provider, error) {
+	registryMu.RLock()
+	factory, ok := registry[id]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q (available: %v)", id, IDs())
+	}
+
+	p, err := factory(opts)
+	if err != nil {
+		return nil, fmt.Errorf("creating provider %q: %w", id, err)
+	}
+
+	return p, nil
+}
+
+// IDs returns the sorted IDs of all registered providers.
+func IDs() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	ids := make([]string, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
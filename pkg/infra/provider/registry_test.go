@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRegistry_IDs(t *testing.T) {
+	for _, id := range []string{"babylon", "ical", "yorck"} {
+		if !slices.Contains(IDs(), id) {
+			t.Errorf("IDs() = %v, missing %q", IDs(), id)
+		}
+	}
+}
+
+func TestRegistry_New(t *testing.T) {
+	p, err := New("babylon", Options{BaseURL: "http://localhost"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := p.(*Babylon).baseURL; got != "http://localhost" {
+		t.Errorf("baseURL = %q, want %q", got, "http://localhost")
+	}
+
+	if _, err := New("unknown", Options{}); err == nil {
+		t.Errorf("New(%q) expected error", "unknown")
+	}
+	if _, err := New("ical", Options{}); err == nil {
+		t.Errorf("New(%q) without sources expected error", "ical")
+	}
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,8 +11,8 @@ import (
 	"time"
 
 	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpclient"
 	"github.com/PhilippReinke/kino-berlin/pkg/infra/provider/yorckmodel"
-	"github.com/gocolly/colly/v2"
 )
 
 const (
@@ -20,16 +21,32 @@ const (
 )
 
 type Yorck struct {
-	c       *colly.Collector
+	client  *http.Client
 	baseURL string
+	last    *lastResult
 }
 
 var _ domain.Provider = &Yorck{}
 
+func init() {
+	Register("yorck", func(opts Options) (domain.Provider, error) {
+		y := newYorck(opts.client())
+		if opts.BaseURL != "" {
+			y.baseURL = opts.BaseURL
+		}
+		return y, nil
+	})
+}
+
 func NewYorck() *Yorck {
+	return newYorck(httpclient.New(httpclient.Config{}))
+}
+
+func newYorck(client *httpclient.Client) *Yorck {
 	return &Yorck{
-		c:       colly.NewCollector(),
+		client:  client.HTTP(),
 		baseURL: "https://www.yorck.de",
+		last:    &lastResult{},
 	}
 }
 
@@ -40,12 +57,24 @@ func (y Yorck) Name() string {
 func (y Yorck) Scrape() ([]domain.Screening, error) {
 	yorckAddress := fmt.Sprintf("%v/%v", y.baseURL, "filme")
 
-	res, err := http.Get(yorckAddress)
+	req, err := http.NewRequestWithContext(y.last.context(), http.MethodGet, yorckAddress, nil)
+	if err != nil {
+		return []domain.Screening{}, fmt.Errorf("creating request: %w", err)
+	}
+
+	res, err := y.client.Do(req)
+	if errors.Is(err, httpclient.ErrNotModified) {
+		return y.last.refreshed(time.Now()), nil
+	}
 	if err != nil {
 		return []domain.Screening{}, fmt.Errorf("fetching from %q: %w", yorckAddress, err)
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode != http.StatusOK {
+		return []domain.Screening{}, &domain.HTTPError{URL: yorckAddress, StatusCode: res.StatusCode}
+	}
+
 	bodyByte, err := io.ReadAll(res.Body)
 	if err != nil {
 		return []domain.Screening{}, fmt.Errorf("reading body: %w", err)
@@ -69,13 +98,29 @@ func (y Yorck) Scrape() ([]domain.Screening, error) {
 		return []domain.Screening{}, fmt.Errorf("unmarshaling JSON: %w", err)
 	}
 
+	screenings, err := y.screenings(films, yorckAddress, time.Now())
+	if err != nil {
+		return []domain.Screening{}, err
+	}
+
+	y.last.set(screenings)
+
+	return screenings, nil
+}
+
+func (y Yorck) screenings(films yorckmodel.FilmsYorck, yorckAddress string, now time.Time) ([]domain.Screening, error) {
 	tz, err := time.LoadLocation("Europe/Berlin")
 	if err != nil {
-		return []domain.Screening{}, fmt.Errorf("creating timezone: %w", err)
+		return nil, fmt.Errorf("creating timezone: %w", err)
 	}
 
 	var screenings []domain.Screening
 	for _, film := range films.Props.PageProps.Films {
+		var ageRating string
+		if film.Fields.FSK.Valid {
+			ageRating = fmt.Sprintf("FSK %d", film.Fields.FSK.Age)
+		}
+
 		for _, session := range film.Fields.Sessions {
 			title := film.Fields.Title
 			start := time.Date(
@@ -89,7 +134,7 @@ func (y Yorck) Scrape() ([]domain.Screening, error) {
 				tz,
 			)
 			cinema := session.Fields.Cinema.Fields.Name
-			language := ""
+			language := session.Fields.Version
 			screeningID := domain.NewScreeningID(
 				title,
 				start,
@@ -98,19 +143,43 @@ func (y Yorck) Scrape() ([]domain.Screening, error) {
 			)
 			duration := time.Minute * time.Duration(film.Fields.Runtime)
 
+			var notes []string
+			for _, label := range session.Fields.Labels {
+				if label.Fields.Name != "" {
+					notes = append(notes, label.Fields.Name)
+				}
+			}
+
+			tagTexts := []string{title}
+			tagTexts = append(tagTexts, session.Fields.Formats...)
+			tagTexts = append(tagTexts, notes...)
+			tagTexts = append(tagTexts, film.Fields.Genres...)
+
 			screenings = append(screenings, domain.Screening{
-				ID:          screeningID,
-				Title:       title,
-				Description: "",
-				Start:       start,
-				Duration:    duration,
-				Cinema:      cinema,
-				Language:    language,
+				ID:           screeningID,
+				Title:        title,
+				Description:  string(film.Fields.Synopsis),
+				Start:        start,
+				Duration:     duration,
+				Cinema:       cinema,
+				Language:     language,
+				Director:     strings.Join(film.Fields.Director, ", "),
+				Cast:         film.Fields.Cast,
+				Genres:       film.Fields.Genres,
+				AgeRating:    ageRating,
+				Year:         film.Fields.Year,
+				Country:      strings.Join(film.Fields.Country, "/"),
+				Notes:        notes,
+				Format:       strings.Join(session.Fields.Formats, ", "),
+				Tags:         domain.DetectTags(tagTexts...),
+				Availability: yorckAvailability(session.Fields),
+				Prices:       yorckPrices(session.Fields),
 				Links: domain.ScreeningLinks{
 					Details:       fmt.Sprintf("%v/%v", yorckAddress, film.Fields.Slug),
+					Booking:       y.absoluteURL(session.Fields.TicketURL),
 					ThumbnailLink: createThumbnailLink(film.Fields.HeroImage.Fields.Image.FieldsImage.File.URL),
 				},
-				UpdatedAt: time.Now(),
+				UpdatedAt: now,
 			})
 		}
 	}
@@ -118,6 +187,54 @@ func (y Yorck) Scrape() ([]domain.Screening, error) {
 	return screenings, nil
 }
 
+func yorckPrices(session yorckmodel.FieldsSessions) domain.Prices {
+	if session.Price == nil {
+		return domain.Prices{}
+	}
+
+	currency := session.Price.Currency
+	if currency == "" {
+		currency = "EUR"
+	}
+
+	return domain.Prices{
+		Currency: currency,
+		Regular:  domain.MoneyFromFloat(session.Price.Regular),
+		Reduced:  domain.MoneyFromFloat(session.Price.Reduced),
+	}
+}
+
+func yorckAvailability(session yorckmodel.FieldsSessions) domain.Availability {
+	switch {
+	case session.SoldOut:
+		return domain.AvailabilitySoldOut
+	case session.AlmostSoldOut:
+		return domain.AvailabilityFewSeats
+	case session.TicketURL != "":
+		// the flags are only set if they apply, so a bookable session
+		// without them has seats left
+		return domain.AvailabilityAvailable
+	default:
+		return domain.AvailabilityUnknown
+	}
+}
+
+// absoluteURL resolves links relative to the Yorck website.
+func (y Yorck) absoluteURL(link string) string {
+	if link == "" {
+		return ""
+	}
+	base, err := url.Parse(y.baseURL)
+	if err != nil {
+		return link
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return base.ResolveReference(ref).String()
+}
+
 func createThumbnailLink(thumbnailURL string) string {
 	u, _ := url.Parse("https:" + thumbnailURL)
 	q := u.Query()
@@ -1,14 +1,163 @@
 package provider
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+	"github.com/PhilippReinke/kino-berlin/pkg/infra/httpclient"
+)
 
 func TestYorck_Name(t *testing.T) {
-	b := NewBabylon()
-	if got := b.Name(); got != "Yorck Kinos" {
+	y := NewYorck()
+	if got := y.Name(); got != "Yorck Kinos" {
 		t.Errorf("Name() = %q, want %q", got, "Yorck Kinos")
 	}
 }
 
 func TestYorck_Scrape(t *testing.T) {
-	// TODO
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /filme", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/yorck.html")
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	y := newYorck(httpclient.New(httpclient.Config{MinInterval: time.Millisecond}))
+	y.baseURL = srv.URL
+
+	screenings, err := y.Scrape()
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if len(screenings) != 4 {
+		t.Fatalf("Scrape() returned %d screenings, want 4", len(screenings))
+	}
+
+	berlin := berlinLocation(t)
+	tests := []struct {
+		name string
+		got  domain.Screening
+		want domain.Screening
+	}{
+		{
+			name: "rich text synopsis and single director",
+			got:  screenings[0],
+			want: domain.Screening{
+				Title:        "Perfect Days",
+				Description:  "Hirayama reinigt öffentliche Toiletten in Tokio.\n\nEr scheint mit seinem einfachen Leben zufrieden.",
+				Start:        time.Date(2025, 12, 30, 18, 15, 0, 0, berlin),
+				Duration:     124 * time.Minute,
+				Cinema:       "Delphi LUX",
+				Language:     "OmU",
+				Director:     "Wim Wenders",
+				Cast:         []string{"Koji Yakusho", "Tokio Emoto", "Arisa Nakano"},
+				Genres:       []string{"Drama"},
+				AgeRating:    "FSK 0",
+				Year:         2023,
+				Country:      "JP/DE",
+				Format:       "DCP",
+				Availability: domain.AvailabilityAvailable,
+				Prices:       domain.Prices{Currency: "EUR", Regular: 1350, Reduced: 1100},
+				Links: domain.ScreeningLinks{
+					Details:       srv.URL + "/filme/perfect-days",
+					Booking:       srv.URL + "/tickets/session-1",
+					ThumbnailLink: "https://images.ctfassets.net/abc/perfect-days.jpg?q=75&w=480",
+				},
+			},
+		},
+		{
+			name: "sold out special event",
+			got:  screenings[1],
+			want: domain.Screening{
+				Title:        "Perfect Days",
+				Description:  "Hirayama reinigt öffentliche Toiletten in Tokio.\n\nEr scheint mit seinem einfachen Leben zufrieden.",
+				Start:        time.Date(2025, 12, 31, 20, 30, 0, 0, berlin),
+				Duration:     124 * time.Minute,
+				Cinema:       "Babylon Kreuzberg",
+				Language:     "OmU",
+				Director:     "Wim Wenders",
+				Cast:         []string{"Koji Yakusho", "Tokio Emoto", "Arisa Nakano"},
+				Genres:       []string{"Drama"},
+				AgeRating:    "FSK 0",
+				Year:         2023,
+				Country:      "JP/DE",
+				Notes:        []string{"Silvester-Special"},
+				Format:       "35mm",
+				Tags:         domain.Tags{domain.Tag35mm},
+				Availability: domain.AvailabilitySoldOut,
+				Links: domain.ScreeningLinks{
+					Details:       srv.URL + "/filme/perfect-days",
+					Booking:       "https://tickets.yorck.de/session-2",
+					ThumbnailLink: "https://images.ctfassets.net/abc/perfect-days.jpg?q=75&w=480",
+				},
+			},
+		},
+		{
+			name: "plain text fields",
+			got:  screenings[2],
+			want: domain.Screening{
+				Title:        "Wicked: For Good",
+				Description:  "Elphaba und Glinda müssen sich entscheiden.",
+				Start:        time.Date(2026, 1, 2, 17, 0, 0, 0, berlin),
+				Duration:     137 * time.Minute,
+				Cinema:       "Kino International",
+				Language:     "OV",
+				Director:     "Jon M. Chu",
+				Cast:         []string{"Cynthia Erivo", "Ariana Grande"},
+				Genres:       []string{"Musical", "Fantasy"},
+				AgeRating:    "FSK 12",
+				Year:         2025,
+				Country:      "USA",
+				Notes:        []string{"Preview", "Q&A mit dem Team"},
+				Format:       "DCP, Dolby Atmos",
+				Tags:         domain.Tags{domain.TagDolbyAtmos, domain.TagPreview, domain.TagGuests},
+				Availability: domain.AvailabilityFewSeats,
+				Links: domain.ScreeningLinks{
+					Details:       srv.URL + "/filme/wicked-for-good",
+					Booking:       srv.URL + "/tickets/session-3",
+					ThumbnailLink: "https://images.ctfassets.net/abc/wicked.jpg?q=75&w=480",
+				},
+			},
+		},
+		{
+			name: "missing metadata",
+			got:  screenings[3],
+			want: domain.Screening{
+				Title:    "Der Zauberer von Oz",
+				Start:    time.Date(2026, 1, 3, 12, 0, 0, 0, berlin),
+				Duration: 102 * time.Minute,
+				Cinema:   "Rollberg",
+				Genres:   []string{"Kinderfilm", "Musical"},
+				Tags:     domain.Tags{domain.TagKids},
+				Links: domain.ScreeningLinks{
+					Details:       srv.URL + "/filme/der-zauberer-von-oz",
+					ThumbnailLink: "https://images.ctfassets.net/abc/oz.jpg?q=75&w=480",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.got
+			if got.UpdatedAt.IsZero() {
+				t.Error("UpdatedAt is not set")
+			}
+			got.UpdatedAt = time.Time{}
+
+			tt.want.ID = domain.NewScreeningID(tt.want.Title, tt.want.Start, tt.want.Cinema, tt.want.Language)
+			if !got.Start.Equal(tt.want.Start) {
+				t.Errorf("Start = %v, want %v", got.Start, tt.want.Start)
+			}
+			got.Start = tt.want.Start
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("screening = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
 }
@@ -1,6 +1,12 @@
 package yorckmodel
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type FilmsYorck struct {
 	Props Props `json:"props"`
@@ -24,6 +30,13 @@ type FieldsFilms struct {
 	Sessions  []Sessions `json:"sessions"`
 	HeroImage HeroImage  `json:"heroImage"`
 	Slug      string     `json:"slug"`
+	Synopsis  RichText   `json:"synopsis"`
+	Director  StringList `json:"director"`
+	Cast      StringList `json:"cast"`
+	Genres    StringList `json:"genres"`
+	FSK       FSK        `json:"fsk"`
+	Year      int        `json:"year"`
+	Country   StringList `json:"country"`
 }
 
 type Sessions struct {
@@ -33,6 +46,33 @@ type Sessions struct {
 type FieldsSessions struct {
 	StartTime time.Time `json:"startTime"`
 	Cinema    Cinema    `json:"cinema"`
+	// Version is the language version like "OV" or "OmU".
+	Version string `json:"version"`
+	// Formats are projection formats like "35mm" or "3D".
+	Formats   []string `json:"formats"`
+	TicketURL string   `json:"ticketUrl"`
+	SoldOut   bool     `json:"soldOut"`
+	// AlmostSoldOut is set if only a few seats are left.
+	AlmostSoldOut bool `json:"almostSoldOut"`
+	// Labels mark special events like previews or Q&As.
+	Labels []Label `json:"labels"`
+	// Price is nil if the session has no published price.
+	Price *Price `json:"price"`
+}
+
+// Price holds ticket prices in the main unit of the currency, e.g. 12.5.
+type Price struct {
+	Currency string  `json:"currency"`
+	Regular  float64 `json:"regular"`
+	Reduced  float64 `json:"reduced"`
+}
+
+type Label struct {
+	Fields FieldsLabel `json:"fields"`
+}
+
+type FieldsLabel struct {
+	Name string `json:"name"`
 }
 
 type Cinema struct {
@@ -62,3 +102,123 @@ type FieldsImage struct {
 type File struct {
 	URL string `json:"url"`
 }
+
+// StringList is a list of names that the payload gives either as an array or
+// as a single comma separated string. Values that are no names, like numbers
+// in the array, are skipped instead of failing the whole payload.
+type StringList []string
+
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("decoding string list: %w", err)
+	}
+
+	var names []string
+	switch v := v.(type) {
+	case []any:
+		for _, elem := range v {
+			if name, ok := elem.(string); ok {
+				names = append(names, name)
+			}
+		}
+	case string:
+		names = strings.Split(v, ",")
+	}
+	*l = trimAll(names)
+
+	return nil
+}
+
+func trimAll(list []string) []string {
+	var trimmed []string
+	for _, s := range list {
+		if s = strings.TrimSpace(s); s != "" {
+			trimmed = append(trimmed, s)
+		}
+	}
+	return trimmed
+}
+
+// FSK is the age rating of the Freiwillige Selbstkontrolle der
+// Filmwirtschaft. The payload gives it as a number or as text like "FSK 12".
+// Valid is false if the film is not rated or the rating is not understood.
+type FSK struct {
+	Age   int
+	Valid bool
+}
+
+func (f *FSK) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("decoding FSK: %w", err)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		*f = FSK{}
+	case float64:
+		*f = FSK{Age: int(v), Valid: true}
+	case string:
+		digits := strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(v)), "FSK"))
+		if digits == "" {
+			*f = FSK{}
+			return nil
+		}
+		age, err := strconv.Atoi(digits)
+		if err != nil {
+			*f = FSK{}
+			return nil
+		}
+		*f = FSK{Age: age, Valid: true}
+	default:
+		*f = FSK{}
+	}
+
+	return nil
+}
+
+// RichText is text that is either a plain string or a Contentful rich text
+// document. Paragraphs are separated by blank lines.
+type RichText string
+
+type richTextNode struct {
+	NodeType string         `json:"nodeType"`
+	Value    string         `json:"value"`
+	Content  []richTextNode `json:"content"`
+}
+
+func (t *RichText) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = RichText(strings.TrimSpace(s))
+		return nil
+	}
+
+	var doc richTextNode
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("decoding rich text: %w", err)
+	}
+
+	var paragraphs []string
+	for _, block := range doc.Content {
+		if text := strings.TrimSpace(block.text()); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	}
+	*t = RichText(strings.Join(paragraphs, "\n\n"))
+
+	return nil
+}
+
+func (n richTextNode) text() string {
+	if n.NodeType == "text" {
+		return n.Value
+	}
+
+	var sb strings.Builder
+	for _, child := range n.Content {
+		sb.WriteString(child.text())
+	}
+	return sb.String()
+}
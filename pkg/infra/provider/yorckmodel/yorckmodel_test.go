@@ -0,0 +1,53 @@
+package yorckmodel
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFieldsFilms_UnexpectedValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    FieldsFilms
+	}{
+		{
+			name:    "rating as text",
+			payload: `{"title": "A", "fsk": "FSK 12", "director": "Ann Smith, Bo Li"}`,
+			want:    FieldsFilms{Title: "A", FSK: FSK{Age: 12, Valid: true}, Director: StringList{"Ann Smith", "Bo Li"}},
+		},
+		{
+			name:    "unknown rating",
+			payload: `{"title": "B", "fsk": "FSK ab 12"}`,
+			want:    FieldsFilms{Title: "B"},
+		},
+		{
+			name:    "rating of unexpected type",
+			payload: `{"title": "C", "fsk": {"age": 12}}`,
+			want:    FieldsFilms{Title: "C"},
+		},
+		{
+			name:    "list with other values than names",
+			payload: `{"title": "D", "cast": ["Ann Smith", 42, null, {"name": "Bo Li"}, " Cy Ng "]}`,
+			want:    FieldsFilms{Title: "D", Cast: StringList{"Ann Smith", "Cy Ng"}},
+		},
+		{
+			name:    "list of unexpected type",
+			payload: `{"title": "E", "genres": 7, "country": null}`,
+			want:    FieldsFilms{Title: "E"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FieldsFilms
+			if err := json.Unmarshal([]byte(tt.payload), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/PhilippReinke/kino-berlin/pkg/domain"
 )
@@ -11,6 +12,7 @@ import (
 type Memory struct {
 	mu         sync.RWMutex
 	screenings map[domain.ScreeningID]domain.Screening
+	index      *domain.SearchIndex
 }
 
 var _ domain.Storage = &Memory{}
@@ -19,9 +21,15 @@ func NewMemory() *Memory {
 	return &Memory{
 		mu:         sync.RWMutex{},
 		screenings: make(map[domain.ScreeningID]domain.Screening),
+		index:      domain.NewSearchIndex(),
 	}
 }
 
+// SetTitleAliases sets the alternative titles used by Search.
+func (m *Memory) SetTitleAliases(aliases *domain.TitleAliases) {
+	m.index.SetAliases(aliases)
+}
+
 func (m *Memory) Upsert(s domain.Screening) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -30,44 +38,67 @@ func (m *Memory) Upsert(s domain.Screening) error {
 	if ok && current.UpdatedAt.After(s.UpdatedAt) {
 		return fmt.Errorf("existing screening is newer")
 	}
+	switch {
+	case ok:
+		s.AddedAt = current.AddedAt
+	case s.AddedAt.IsZero():
+		s.AddedAt = time.Now()
+	}
 
 	m.screenings[s.ID] = s
+	m.index.Add(s)
 
 	return nil
 }
 
-func (m *Memory) Fetch(filter ...domain.Filter) ([]domain.Screening, error) {
+func (m *Memory) Fetch(page domain.Page, filter ...domain.Filter) ([]domain.Screening, int, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	var screenings []domain.Screening
 	for _, s := range m.screenings {
-		keep := true
-		for _, f := range filter {
-			if !f(s) {
-				keep = false
-				break
-			}
+		if matches(s, filter) {
+			screenings = append(screenings, s)
 		}
+	}
+
+	page.Order.Sort(screenings)
+
+	return page.Slice(screenings), len(screenings), nil
+}
+
+func (m *Memory) Search(query string, page domain.Page, filter ...domain.Filter) ([]domain.Screening, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := m.index.Search(query)
+	scores := make(map[domain.ScreeningID]int, len(results))
 
-		if keep {
+	var screenings []domain.Screening
+	for _, r := range results {
+		s, ok := m.screenings[r.ID]
+		if ok && matches(s, filter) {
 			screenings = append(screenings, s)
+			scores[s.ID] = r.Score
 		}
 	}
 
-	// Sort screenings for deterministic order:
-	// 1. By start time (earliest first)
-	// 2. By cinema (alphabetically)
-	// 3. By title (alphabetically)
-	sort.Slice(screenings, func(i, j int) bool {
-		if !screenings[i].Start.Equal(screenings[j].Start) {
-			return screenings[i].Start.Before(screenings[j].Start)
-		}
-		if screenings[i].Cinema != screenings[j].Cinema {
-			return screenings[i].Cinema < screenings[j].Cinema
-		}
-		return screenings[i].Title < screenings[j].Title
-	})
+	// Best matches first, equally good matches in the order of Fetch.
+	page.Order.Sort(screenings)
+	if page.Order == "" {
+		sort.SliceStable(screenings, func(i, j int) bool {
+			return scores[screenings[i].ID] > scores[screenings[j].ID]
+		})
+	}
+
+	return page.Slice(screenings), len(screenings), nil
+}
 
-	return screenings, nil
+func matches(s domain.Screening, filter []domain.Filter) bool {
+	for _, f := range filter {
+		if !f(s) {
+			return false
+		}
+	}
+	return true
 }
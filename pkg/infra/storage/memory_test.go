@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/PhilippReinke/kino-berlin/pkg/domain"
+)
+
+func TestMemory_Search(t *testing.T) {
+	now := time.Date(2025, 3, 1, 20, 0, 0, 0, time.UTC)
+	m := NewMemory()
+
+	for _, s := range []domain.Screening{
+		{ID: "1", Title: "Kino der Angst", Cinema: "Babylon", Start: now, UpdatedAt: now},
+		{ID: "2", Title: "Angst essen Seele auf", Cinema: "Babylon", Start: now.Add(time.Hour), UpdatedAt: now},
+		{ID: "3", Title: "Angst", Cinema: "Yorck", Start: now.Add(2 * time.Hour), UpdatedAt: now},
+		{ID: "4", Title: "Die Angst des Tormanns", Description: "Wim Wenders", Cinema: "Yorck", Start: now.Add(-time.Hour), UpdatedAt: now},
+	} {
+		if err := m.Upsert(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the index follows updates
+	if err := m.Upsert(domain.Screening{ID: "4", Title: "Der Himmel über Berlin", Cinema: "Yorck", Start: now.Add(-time.Hour), UpdatedAt: now.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := m.Search("angst", domain.Page{}, domain.CinemaFilter("Babylon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "1" || got[1].ID != "2" {
+		t.Errorf("Search(angst) = %v, want screenings 1 and 2 by start", ids(got))
+	}
+
+	got, _, err = m.Search("über himmel", domain.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != "4" {
+		t.Errorf("Search(über himmel) = %v, want screening 4", ids(got))
+	}
+
+	got, _, err = m.Search("wenders", domain.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Search(wenders) = %v, want none after update", ids(got))
+	}
+}
+
+func TestMemory_Fetch(t *testing.T) {
+	now := time.Date(2025, 3, 1, 20, 0, 0, 0, time.UTC)
+	m := NewMemory()
+
+	for _, s := range []domain.Screening{
+		{ID: "1", Title: "Stalker", Cinema: "Babylon", Start: now, Duration: 161 * time.Minute, UpdatedAt: now, AddedAt: now},
+		{ID: "2", Title: "Alien", Cinema: "Yorck", Start: now.Add(time.Hour), Duration: 117 * time.Minute, UpdatedAt: now, AddedAt: now.Add(time.Hour)},
+		{ID: "3", Title: "Ödipussi", Cinema: "Babylon", Start: now.Add(2 * time.Hour), UpdatedAt: now, AddedAt: now.Add(-time.Hour)},
+		{ID: "4", Title: "Metropolis", Cinema: "Arsenal", Start: now.Add(-time.Hour), Duration: 153 * time.Minute, UpdatedAt: now, AddedAt: now},
+	} {
+		if err := m.Upsert(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// updates keep the time the screening was added
+	if err := m.Upsert(domain.Screening{ID: "3", Title: "Ödipussi", Cinema: "Babylon", Start: now.Add(2 * time.Hour), UpdatedAt: now.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		page  domain.Page
+		want  []domain.ScreeningID
+		total int
+	}{
+		{domain.Page{}, []domain.ScreeningID{"4", "1", "2", "3"}, 4},
+		{domain.Page{Order: domain.OrderTitle}, []domain.ScreeningID{"2", "4", "3", "1"}, 4},
+		{domain.Page{Order: domain.OrderCinema}, []domain.ScreeningID{"4", "1", "3", "2"}, 4},
+		{domain.Page{Order: domain.OrderRuntime}, []domain.ScreeningID{"2", "4", "1", "3"}, 4},
+		{domain.Page{Order: domain.OrderAdded}, []domain.ScreeningID{"2", "4", "1", "3"}, 4},
+		{domain.Page{Offset: 1, Limit: 2}, []domain.ScreeningID{"1", "2"}, 4},
+		{domain.Page{Offset: 3, Limit: 2}, []domain.ScreeningID{"3"}, 4},
+		{domain.Page{Offset: 5}, []domain.ScreeningID{}, 4},
+	}
+
+	for _, tt := range tests {
+		got, total, err := m.Fetch(tt.page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(ids(got), tt.want) || total != tt.total {
+			t.Errorf("Fetch(%+v) = %v, %d; want %v, %d", tt.page, ids(got), total, tt.want, tt.total)
+		}
+	}
+
+	got, total, err := m.Fetch(domain.Page{Limit: 1}, domain.CinemaFilter("Babylon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != "1" || total != 2 {
+		t.Errorf("Fetch(limit 1, Babylon) = %v, %d; want [1], 2", ids(got), total)
+	}
+}
+
+func ids(screenings []domain.Screening) []domain.ScreeningID {
+	ids := make([]domain.ScreeningID, len(screenings))
+	for i, s := range screenings {
+		ids[i] = s.ID
+	}
+	return ids
+}
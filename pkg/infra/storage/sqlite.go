@@ -15,12 +15,12 @@ func NewSQLite() *SQLite {
 
 func (s *SQLite) Upsert(screening domain.Screening) error {
 	panic("unimplemented")
-
-	return nil
 }
 
-func (s *SQLite) Fetch(filter ...domain.Filter) ([]domain.Screening, error) {
+func (s *SQLite) Fetch(page domain.Page, filter ...domain.Filter) ([]domain.Screening, int, error) {
 	panic("unimplemented")
+}
 
-	return []domain.Screening{}, nil
+func (s *SQLite) Search(query string, page domain.Page, filter ...domain.Filter) ([]domain.Screening, int, error) {
+	panic("unimplemented")
 }
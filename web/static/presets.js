@@ -0,0 +1,114 @@
+// Filter presets: the filters of the form can be saved under a name in the
+// browser, recalled with one click and shared as a link. A link is the page
+// URL with the form fields as query parameters, the server fills in the form
+// when it is opened.
+(function () {
+    const storageKey = "kino-berlin.presets";
+
+    const form = document.getElementById("filters");
+    const list = document.getElementById("preset-list");
+    const nameInput = document.getElementById("preset-name");
+    // translated texts
+    const labels = document.getElementById("presets").dataset;
+
+    function loadPresets() {
+        try {
+            return JSON.parse(localStorage.getItem(storageKey)) || {};
+        } catch {
+            return {};
+        }
+    }
+
+    function savePresets(presets) {
+        localStorage.setItem(storageKey, JSON.stringify(presets));
+    }
+
+    function formParams() {
+        const params = new URLSearchParams();
+        for (const [name, value] of new FormData(form)) {
+            if (value !== "") {
+                params.append(name, value);
+            }
+        }
+        return params;
+    }
+
+    function applyParams(params) {
+        for (const el of form.elements) {
+            if (!el.name) {
+                continue;
+            }
+            const values = params.getAll(el.name);
+            if (el.type === "checkbox" || el.type === "radio") {
+                el.checked = values.includes(el.value);
+            } else if (el.tagName === "SELECT" && el.multiple) {
+                for (const option of el.options) {
+                    option.selected = values.includes(option.value);
+                }
+            } else if (el.tagName !== "BUTTON") {
+                el.value = values[0] || "";
+            }
+        }
+        htmx.trigger(form, "submit");
+    }
+
+    function shareURL(params) {
+        return location.origin + location.pathname + (params.size ? "?" + params : "");
+    }
+
+    function render() {
+        list.replaceChildren();
+        for (const [name, query] of Object.entries(loadPresets())) {
+            const item = document.createElement("li");
+
+            const recall = document.createElement("button");
+            recall.type = "button";
+            recall.textContent = name;
+            recall.title = labels.apply;
+            recall.addEventListener("click", () => applyParams(new URLSearchParams(query)));
+
+            const remove = document.createElement("button");
+            remove.type = "button";
+            remove.className = "remove";
+            remove.textContent = "×";
+            remove.title = labels.delete;
+            remove.addEventListener("click", () => {
+                const presets = loadPresets();
+                delete presets[name];
+                savePresets(presets);
+                render();
+            });
+
+            item.append(recall, remove);
+            list.append(item);
+        }
+    }
+
+    document.getElementById("preset-save").addEventListener("click", () => {
+        const name = nameInput.value.trim();
+        if (!name) {
+            nameInput.focus();
+            return;
+        }
+        const presets = loadPresets();
+        presets[name] = formParams().toString();
+        savePresets(presets);
+        nameInput.value = "";
+        render();
+    });
+
+    const share = document.getElementById("preset-share").textContent;
+    document.getElementById("preset-share").addEventListener("click", async (event) => {
+        try {
+            await navigator.clipboard.writeText(shareURL(formParams()));
+            event.target.textContent = labels.copied;
+        } catch {
+            // submitting pushes the link to the address bar
+            htmx.trigger(form, "submit");
+            event.target.textContent = labels.inAddressBar;
+        }
+        setTimeout(() => { event.target.textContent = share; }, 2000);
+    });
+
+    render();
+})();
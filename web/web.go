@@ -0,0 +1,28 @@
+// Package web holds the templates and static files of the web UI. They are
+// embedded into the binary so that it runs from any directory.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed templates static
+var files embed.FS
+
+var (
+	// Templates holds the HTML templates, the pages in pages/.
+	Templates = sub("templates")
+	// Static holds the files served as they are. Third-party files are
+	// committed in vendor/, like htmx 2.0.3 from
+	// https://unpkg.com/htmx.org@2.0.3/dist/htmx.min.js.
+	Static = sub("static")
+)
+
+func sub(dir string) fs.FS {
+	f, err := fs.Sub(files, dir)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,21 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatic_Vendor(t *testing.T) {
+	// the layout loads htmx from here, without it the UI does not work
+	req := httptest.NewRequest(http.MethodGet, "/vendor/htmx.min.js", nil)
+	rec := httptest.NewRecorder()
+	http.FileServerFS(Static).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /vendor/htmx.min.js = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.Len() == 0 {
+		t.Error("htmx.min.js is empty")
+	}
+}